@@ -0,0 +1,44 @@
+// Package cases implements the analyst follow-up workflow for transactions
+// that RuleBasedFraudDetector did not approve outright: opening a case,
+// tracking its investigation through to a verdict, and feeding that verdict
+// back into the blacklists and model-retraining pipeline.
+package cases
+
+import "time"
+
+// Status is the lifecycle state of a Case.
+type Status string
+
+const (
+	StatusOpen           Status = "open"
+	StatusInvestigating  Status = "investigating"
+	StatusConfirmedFraud Status = "confirmed_fraud"
+	StatusCleared        Status = "cleared"
+)
+
+// AuditEntry records one state change or comment applied to a Case, for the
+// compliance trail analysts are expected to leave behind.
+type AuditEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Actor     string    `json:"actor"`
+	Action    string    `json:"action"`
+	Detail    string    `json:"detail,omitempty"`
+}
+
+// Case is the analyst-facing record opened for a transaction that didn't
+// land on "approve". TransactionData is the snapshot the decision was made
+// against, kept around so a later confirmed_fraud verdict has enough
+// context to backfill the blacklists without re-fetching the transaction.
+type Case struct {
+	ID              string                 `json:"id"`
+	TransactionRef  string                 `json:"transaction_ref"`
+	Score           float64                `json:"score"`
+	Reasons         []string               `json:"reasons"`
+	Status          Status                 `json:"status"`
+	Assignee        string                 `json:"assignee,omitempty"`
+	Notes           []string               `json:"notes,omitempty"`
+	AuditLog        []AuditEntry           `json:"audit_log"`
+	TransactionData map[string]interface{} `json:"transaction_data,omitempty"`
+	CreatedAt       time.Time              `json:"created_at"`
+	UpdatedAt       time.Time              `json:"updated_at"`
+}
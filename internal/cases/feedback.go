@@ -0,0 +1,63 @@
+package cases
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// FeedbackLabel is the ground-truth outcome an analyst assigns to a case,
+// fed back to the scoring engine so future model retraining can learn from
+// confirmed outcomes instead of only the rules that happened to trigger.
+type FeedbackLabel string
+
+const (
+	FeedbackConfirmedFraud FeedbackLabel = "confirmed_fraud"
+	FeedbackCleared        FeedbackLabel = "cleared"
+)
+
+// FeedbackRecord pairs a transaction's original feature snapshot with the
+// label an analyst ultimately assigned it.
+type FeedbackRecord struct {
+	TransactionRef string                 `json:"transaction_ref"`
+	Label          FeedbackLabel          `json:"label"`
+	Features       map[string]interface{} `json:"features"`
+	RecordedAt     time.Time              `json:"recorded_at"`
+}
+
+// FeedbackRepository stores analyst-confirmed labels for later model
+// retraining. It is kept separate from CaseRepository so the training
+// pipeline can consume it independently of the case workflow.
+type FeedbackRepository interface {
+	Record(ctx context.Context, rec FeedbackRecord) error
+}
+
+// InMemoryFeedbackRepository is a FeedbackRepository useful for local
+// development and tests; a production deployment would back this with
+// whatever warehouse the model training pipeline reads from.
+type InMemoryFeedbackRepository struct {
+	mu      sync.Mutex
+	records []FeedbackRecord
+}
+
+// NewInMemoryFeedbackRepository creates an empty in-memory repository.
+func NewInMemoryFeedbackRepository() *InMemoryFeedbackRepository {
+	return &InMemoryFeedbackRepository{}
+}
+
+// Record appends rec.
+func (r *InMemoryFeedbackRepository) Record(_ context.Context, rec FeedbackRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = append(r.records, rec)
+	return nil
+}
+
+// Records returns every label recorded so far, for tests and offline export.
+func (r *InMemoryFeedbackRepository) Records() []FeedbackRecord {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]FeedbackRecord, len(r.records))
+	copy(out, r.records)
+	return out
+}
@@ -0,0 +1,147 @@
+package cases
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/kodra-pay/fraud-service/internal/repository"
+)
+
+// CaseManager is the entry point for the analyst case workflow: it opens
+// cases for flagged transactions, applies analyst-driven status
+// transitions, and on a terminal verdict feeds the outcome back into
+// FeedbackRepository and, for confirmed fraud, the blacklist repositories so
+// the next transaction from the same IP/device/card BIN is caught before it
+// ever needs a case.
+type CaseManager struct {
+	cases     CaseRepository
+	feedback  FeedbackRepository
+	blacklist repository.BlacklistRepository
+}
+
+// NewCaseManager wires the repositories a CaseManager needs. blacklist may
+// be nil, in which case confirmed_fraud verdicts are still recorded as
+// feedback but nothing is auto-blacklisted.
+func NewCaseManager(caseRepo CaseRepository, feedback FeedbackRepository, blacklist repository.BlacklistRepository) *CaseManager {
+	return &CaseManager{cases: caseRepo, feedback: feedback, blacklist: blacklist}
+}
+
+// OpenCase opens a case for a transaction that didn't land on "approve",
+// recording score/reasons/transactionData at decision time so a later
+// confirmed_fraud verdict has enough context to backfill the blacklists.
+func (m *CaseManager) OpenCase(ctx context.Context, transactionRef string, transactionData map[string]interface{}, score float64, reasons []string) (*Case, error) {
+	now := time.Now()
+	c := &Case{
+		ID:              newCaseID(),
+		TransactionRef:  transactionRef,
+		Score:           score,
+		Reasons:         reasons,
+		Status:          StatusOpen,
+		TransactionData: transactionData,
+		AuditLog: []AuditEntry{
+			{Timestamp: now, Actor: "system", Action: "opened", Detail: "auto-opened from a non-approve decision"},
+		},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := m.cases.Create(ctx, c); err != nil {
+		return nil, fmt.Errorf("cases: failed to open case for %s: %w", transactionRef, err)
+	}
+	return c, nil
+}
+
+// List returns every case with status, or every case if status is "".
+func (m *CaseManager) List(ctx context.Context, status Status) ([]*Case, error) {
+	return m.cases.List(ctx, status)
+}
+
+// Get returns a single case by ID.
+func (m *CaseManager) Get(ctx context.Context, id string) (*Case, error) {
+	return m.cases.Get(ctx, id)
+}
+
+// UpdateStatus applies a status transition made by actor, e.g. an analyst
+// moving a case from "open" to "investigating". A confirmed_fraud or
+// cleared verdict additionally records feedback for model retraining; a
+// confirmed_fraud verdict also blacklists the transaction's IP, device, and
+// card BIN.
+func (m *CaseManager) UpdateStatus(ctx context.Context, id, actor string, status Status, assignee *string) (*Case, error) {
+	update := CaseUpdate{Assignee: assignee}
+	if status != "" {
+		s := status
+		update.Status = &s
+	}
+
+	c, err := m.cases.Update(ctx, id, update, actor, fmt.Sprintf("status -> %s", status))
+	if err != nil {
+		return nil, fmt.Errorf("cases: failed to update case %s: %w", id, err)
+	}
+
+	switch status {
+	case StatusConfirmedFraud:
+		if err := m.recordOutcome(ctx, c, FeedbackConfirmedFraud); err != nil {
+			return c, err
+		}
+	case StatusCleared:
+		if err := m.recordOutcome(ctx, c, FeedbackCleared); err != nil {
+			return c, err
+		}
+	}
+
+	return c, nil
+}
+
+// AddComment appends an analyst note to the case's audit trail.
+func (m *CaseManager) AddComment(ctx context.Context, id, author, text string) (*Case, error) {
+	c, err := m.cases.AddComment(ctx, id, author, text)
+	if err != nil {
+		return nil, fmt.Errorf("cases: failed to add comment to case %s: %w", id, err)
+	}
+	return c, nil
+}
+
+// recordOutcome feeds a terminal verdict back to the training pipeline and,
+// for confirmed fraud, blacklists the IP/device/card BIN the transaction
+// used so future transactions from the same origin are caught up front.
+func (m *CaseManager) recordOutcome(ctx context.Context, c *Case, label FeedbackLabel) error {
+	if err := m.feedback.Record(ctx, FeedbackRecord{
+		TransactionRef: c.TransactionRef,
+		Label:          label,
+		Features:       c.TransactionData,
+		RecordedAt:     time.Now(),
+	}); err != nil {
+		return fmt.Errorf("cases: failed to record feedback for case %s: %w", c.ID, err)
+	}
+
+	if label != FeedbackConfirmedFraud || m.blacklist == nil {
+		return nil
+	}
+
+	fields := []struct {
+		kind  repository.BlacklistKind
+		field string
+	}{
+		{repository.BlacklistKindIP, "origin"},
+		{repository.BlacklistKindDevice, "device_id"},
+		{repository.BlacklistKindCardBin, "card_bin"},
+	}
+	for _, f := range fields {
+		value, ok := c.TransactionData[f.field].(string)
+		if !ok || value == "" {
+			continue
+		}
+		if err := m.blacklist.Add(ctx, f.kind, value); err != nil {
+			return fmt.Errorf("cases: failed to blacklist %s %q for case %s: %w", f.kind, value, c.ID, err)
+		}
+	}
+	return nil
+}
+
+func newCaseID() string {
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	return "case_" + hex.EncodeToString(buf)
+}
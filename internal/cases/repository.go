@@ -0,0 +1,290 @@
+package cases
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// CaseUpdate carries the mutable fields a PATCH request may change;
+// nil fields are left untouched.
+type CaseUpdate struct {
+	Status   *Status
+	Assignee *string
+}
+
+// CaseRepository persists cases and the status transitions, assignment, and
+// comments the analyst workflow applies to them.
+type CaseRepository interface {
+	Create(ctx context.Context, c *Case) error
+	Get(ctx context.Context, id string) (*Case, error)
+	// List returns every case with status, or every case if status is "".
+	List(ctx context.Context, status Status) ([]*Case, error)
+	Update(ctx context.Context, id string, update CaseUpdate, actor, detail string) (*Case, error)
+	AddComment(ctx context.Context, id string, author, text string) (*Case, error)
+}
+
+// InMemoryCaseRepository is a process-local CaseRepository, useful for tests
+// and local development without Postgres.
+type InMemoryCaseRepository struct {
+	mu    sync.Mutex
+	cases map[string]*Case
+}
+
+// NewInMemoryCaseRepository creates an empty in-memory repository.
+func NewInMemoryCaseRepository() *InMemoryCaseRepository {
+	return &InMemoryCaseRepository{cases: make(map[string]*Case)}
+}
+
+// Create stores c.
+func (r *InMemoryCaseRepository) Create(_ context.Context, c *Case) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cases[c.ID] = c
+	return nil
+}
+
+// Get returns the case with id.
+func (r *InMemoryCaseRepository) Get(_ context.Context, id string) (*Case, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.cases[id]
+	if !ok {
+		return nil, fmt.Errorf("case %s not found", id)
+	}
+	clone := *c
+	return &clone, nil
+}
+
+// List returns every case matching status, or every case if status is "".
+func (r *InMemoryCaseRepository) List(_ context.Context, status Status) ([]*Case, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []*Case
+	for _, c := range r.cases {
+		if status == "" || c.Status == status {
+			clone := *c
+			out = append(out, &clone)
+		}
+	}
+	return out, nil
+}
+
+// Update applies update to the case with id, recording an audit entry.
+func (r *InMemoryCaseRepository) Update(_ context.Context, id string, update CaseUpdate, actor, detail string) (*Case, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.cases[id]
+	if !ok {
+		return nil, fmt.Errorf("case %s not found", id)
+	}
+	if update.Status != nil {
+		c.Status = *update.Status
+	}
+	if update.Assignee != nil {
+		c.Assignee = *update.Assignee
+	}
+	c.UpdatedAt = time.Now()
+	c.AuditLog = append(c.AuditLog, AuditEntry{Timestamp: c.UpdatedAt, Actor: actor, Action: "update", Detail: detail})
+	clone := *c
+	return &clone, nil
+}
+
+// AddComment appends a note to the case with id, recording an audit entry.
+func (r *InMemoryCaseRepository) AddComment(_ context.Context, id string, author, text string) (*Case, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.cases[id]
+	if !ok {
+		return nil, fmt.Errorf("case %s not found", id)
+	}
+	c.Notes = append(c.Notes, fmt.Sprintf("%s: %s", author, text))
+	c.UpdatedAt = time.Now()
+	c.AuditLog = append(c.AuditLog, AuditEntry{Timestamp: c.UpdatedAt, Actor: author, Action: "comment", Detail: text})
+	clone := *c
+	return &clone, nil
+}
+
+// PostgresCaseRepository implements CaseRepository against a fraud_cases
+// table:
+//
+//	CREATE TABLE fraud_cases (
+//		id               TEXT PRIMARY KEY,
+//		transaction_ref  TEXT NOT NULL,
+//		score            DOUBLE PRECISION NOT NULL,
+//		reasons          JSONB NOT NULL DEFAULT '[]',
+//		status           TEXT NOT NULL,
+//		assignee         TEXT NOT NULL DEFAULT '',
+//		notes            JSONB NOT NULL DEFAULT '[]',
+//		audit_log        JSONB NOT NULL DEFAULT '[]',
+//		transaction_data JSONB NOT NULL DEFAULT '{}',
+//		created_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+//		updated_at       TIMESTAMPTZ NOT NULL DEFAULT now()
+//	);
+type PostgresCaseRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresCaseRepository wraps an existing *sql.DB; the service is
+// expected to own connection pooling and migrations.
+func NewPostgresCaseRepository(db *sql.DB) *PostgresCaseRepository {
+	return &PostgresCaseRepository{db: db}
+}
+
+// Create inserts c.
+func (r *PostgresCaseRepository) Create(ctx context.Context, c *Case) error {
+	reasons, err := json.Marshal(c.Reasons)
+	if err != nil {
+		return fmt.Errorf("cases: failed to encode reasons: %w", err)
+	}
+	notes, err := json.Marshal(c.Notes)
+	if err != nil {
+		return fmt.Errorf("cases: failed to encode notes: %w", err)
+	}
+	auditLog, err := json.Marshal(c.AuditLog)
+	if err != nil {
+		return fmt.Errorf("cases: failed to encode audit log: %w", err)
+	}
+	transactionData, err := json.Marshal(c.TransactionData)
+	if err != nil {
+		return fmt.Errorf("cases: failed to encode transaction data: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx,
+		`INSERT INTO fraud_cases
+			(id, transaction_ref, score, reasons, status, assignee, notes, audit_log, transaction_data, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+		c.ID, c.TransactionRef, c.Score, reasons, c.Status, c.Assignee, notes, auditLog, transactionData, c.CreatedAt, c.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("cases: failed to insert case %s: %w", c.ID, err)
+	}
+	return nil
+}
+
+// Get returns the case with id.
+func (r *PostgresCaseRepository) Get(ctx context.Context, id string) (*Case, error) {
+	row := r.db.QueryRowContext(ctx,
+		`SELECT id, transaction_ref, score, reasons, status, assignee, notes, audit_log, transaction_data, created_at, updated_at
+		 FROM fraud_cases WHERE id = $1`, id,
+	)
+	return scanCase(row)
+}
+
+// List returns every case matching status, or every case if status is "".
+func (r *PostgresCaseRepository) List(ctx context.Context, status Status) ([]*Case, error) {
+	query := `SELECT id, transaction_ref, score, reasons, status, assignee, notes, audit_log, transaction_data, created_at, updated_at
+		FROM fraud_cases`
+	args := []interface{}{}
+	if status != "" {
+		query += ` WHERE status = $1`
+		args = append(args, status)
+	}
+	query += ` ORDER BY created_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("cases: failed to list cases: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*Case
+	for rows.Next() {
+		c, err := scanCase(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+// Update applies update to the case with id, recording an audit entry.
+func (r *PostgresCaseRepository) Update(ctx context.Context, id string, update CaseUpdate, actor, detail string) (*Case, error) {
+	c, err := r.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if update.Status != nil {
+		c.Status = *update.Status
+	}
+	if update.Assignee != nil {
+		c.Assignee = *update.Assignee
+	}
+	c.UpdatedAt = time.Now()
+	c.AuditLog = append(c.AuditLog, AuditEntry{Timestamp: c.UpdatedAt, Actor: actor, Action: "update", Detail: detail})
+
+	if err := r.save(ctx, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// AddComment appends a note to the case with id, recording an audit entry.
+func (r *PostgresCaseRepository) AddComment(ctx context.Context, id string, author, text string) (*Case, error) {
+	c, err := r.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	c.Notes = append(c.Notes, fmt.Sprintf("%s: %s", author, text))
+	c.UpdatedAt = time.Now()
+	c.AuditLog = append(c.AuditLog, AuditEntry{Timestamp: c.UpdatedAt, Actor: author, Action: "comment", Detail: text})
+
+	if err := r.save(ctx, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (r *PostgresCaseRepository) save(ctx context.Context, c *Case) error {
+	notes, err := json.Marshal(c.Notes)
+	if err != nil {
+		return fmt.Errorf("cases: failed to encode notes: %w", err)
+	}
+	auditLog, err := json.Marshal(c.AuditLog)
+	if err != nil {
+		return fmt.Errorf("cases: failed to encode audit log: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx,
+		`UPDATE fraud_cases SET status = $1, assignee = $2, notes = $3, audit_log = $4, updated_at = $5 WHERE id = $6`,
+		c.Status, c.Assignee, notes, auditLog, c.UpdatedAt, c.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("cases: failed to update case %s: %w", c.ID, err)
+	}
+	return nil
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanCase(row rowScanner) (*Case, error) {
+	var c Case
+	var reasons, notes, auditLog, transactionData []byte
+	err := row.Scan(&c.ID, &c.TransactionRef, &c.Score, &reasons, &c.Status, &c.Assignee, &notes, &auditLog, &transactionData, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("case not found: %w", err)
+		}
+		return nil, fmt.Errorf("cases: failed to scan case: %w", err)
+	}
+	if err := json.Unmarshal(reasons, &c.Reasons); err != nil {
+		return nil, fmt.Errorf("cases: failed to decode reasons: %w", err)
+	}
+	if err := json.Unmarshal(notes, &c.Notes); err != nil {
+		return nil, fmt.Errorf("cases: failed to decode notes: %w", err)
+	}
+	if err := json.Unmarshal(auditLog, &c.AuditLog); err != nil {
+		return nil, fmt.Errorf("cases: failed to decode audit log: %w", err)
+	}
+	if err := json.Unmarshal(transactionData, &c.TransactionData); err != nil {
+		return nil, fmt.Errorf("cases: failed to decode transaction data: %w", err)
+	}
+	return &c, nil
+}
@@ -0,0 +1,41 @@
+// Package challenges implements step-up verification for transactions the
+// fraud engine scores as medium risk (FraudDecision.Decision == "challenge")
+// instead of outright approving or denying them: a Challenge is created
+// offering a set of verification factors (e.g. an SMS/email OTP or a 3DS
+// redirect), the caller starts one via StartFactor, and Verify confirms the
+// secret they receive back before the transaction is let through.
+package challenges
+
+import "time"
+
+// Status is the lifecycle state of a Challenge.
+type Status string
+
+const (
+	StatusPending  Status = "pending"
+	StatusApproved Status = "approved"
+	StatusLocked   Status = "locked"
+)
+
+// Challenge is a pending step-up verification for one transaction.
+type Challenge struct {
+	ID             string
+	TransactionRef string
+	AllowedFactors []string
+	Status         Status
+	Attempts       int
+
+	// PendingFactor and PendingSecretHash describe the factor most recently
+	// dispatched by StartFactor; Verify checks a secret against them.
+	PendingFactor     string
+	PendingSecretHash string
+
+	// IP and UserAgent are captured when the challenge is created and
+	// compared against every StartFactor/Verify request, so a stolen
+	// challenge ID can't be completed from a different device.
+	IP        string
+	UserAgent string
+
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
@@ -0,0 +1,48 @@
+package challenges
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"log"
+)
+
+// FactorDispatcher sends a factor's one-time secret to the customer (e.g.
+// an SMS/email OTP, or initiating a 3DS redirect) and returns the secret
+// Verify is expected to receive back. Swap in a real SMS/email/3DS
+// integration in production; OTPFactorDispatcher is a working default that
+// only logs the code, for local development.
+type FactorDispatcher interface {
+	Dispatch(ctx context.Context, challenge Challenge, factorID string) (secret string, err error)
+}
+
+// OTPFactorDispatcher generates a 6-digit numeric one-time code and logs it
+// instead of actually sending an SMS/email, standing in until a real
+// provider is wired up.
+type OTPFactorDispatcher struct{}
+
+// NewOTPFactorDispatcher creates an OTPFactorDispatcher.
+func NewOTPFactorDispatcher() *OTPFactorDispatcher {
+	return &OTPFactorDispatcher{}
+}
+
+// Dispatch generates a 6-digit OTP for factorID and logs it as having been
+// "sent" for challenge.TransactionRef.
+func (d *OTPFactorDispatcher) Dispatch(ctx context.Context, challenge Challenge, factorID string) (string, error) {
+	code, err := generateOTP()
+	if err != nil {
+		return "", fmt.Errorf("challenges: failed to generate OTP: %w", err)
+	}
+	log.Printf("challenges: dispatched %s OTP %s for challenge %s (transaction %s)", factorID, code, challenge.ID, challenge.TransactionRef)
+	return code, nil
+}
+
+// generateOTP returns a random 6-digit numeric code, zero-padded.
+func generateOTP() (string, error) {
+	buf := make([]byte, 4)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	n := (uint32(buf[0])<<24 | uint32(buf[1])<<16 | uint32(buf[2])<<8 | uint32(buf[3])) % 1000000
+	return fmt.Sprintf("%06d", n), nil
+}
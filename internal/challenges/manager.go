@@ -0,0 +1,171 @@
+package challenges
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultFactors are the step-up verification factors offered to a
+// challenge when the caller doesn't specify its own set.
+var DefaultFactors = []string{"otp_sms", "otp_email", "3ds"}
+
+// MaxAttempts bounds how many times Verify can be called for a challenge
+// before it locks, regardless of which factor is being attempted.
+const MaxAttempts = 5
+
+// DefaultTTL is how long a challenge stays open to be started/verified when
+// the caller doesn't specify its own.
+const DefaultTTL = 10 * time.Minute
+
+// Manager orchestrates the challenge/step-up-verification lifecycle:
+// creating a Challenge after a "challenge" FraudDecision, dispatching the
+// caller's chosen factor, and verifying the secret they send back.
+type Manager struct {
+	repo       Repository
+	dispatcher FactorDispatcher
+}
+
+// NewManager creates a Manager backed by repo and dispatcher.
+func NewManager(repo Repository, dispatcher FactorDispatcher) *Manager {
+	return &Manager{repo: repo, dispatcher: dispatcher}
+}
+
+// New creates a pending Challenge for transactionRef, recording the
+// requester's IP and User-Agent so Verify can later reject a request from a
+// different device.
+func (m *Manager) New(ctx context.Context, transactionRef string, allowedFactors []string, ttl time.Duration, ip, userAgent string) (Challenge, error) {
+	if len(allowedFactors) == 0 {
+		allowedFactors = DefaultFactors
+	}
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+
+	challenge := Challenge{
+		ID:             newChallengeID(),
+		TransactionRef: transactionRef,
+		AllowedFactors: allowedFactors,
+		Status:         StatusPending,
+		IP:             ip,
+		UserAgent:      userAgent,
+		CreatedAt:      time.Now(),
+		ExpiresAt:      time.Now().Add(ttl),
+	}
+	if err := m.repo.Create(ctx, challenge); err != nil {
+		return Challenge{}, fmt.Errorf("challenges: failed to create challenge: %w", err)
+	}
+	return challenge, nil
+}
+
+// StartFactor dispatches factorID's one-time secret for challengeID via the
+// configured FactorDispatcher, storing its hash for Verify to check against.
+func (m *Manager) StartFactor(ctx context.Context, challengeID, factorID string) error {
+	challenge, err := m.repo.Get(ctx, challengeID)
+	if err != nil {
+		return err
+	}
+	if err := assertFactorAllowed(challenge, factorID); err != nil {
+		return err
+	}
+	if err := assertChallengeOpen(challenge); err != nil {
+		return err
+	}
+
+	secret, err := m.dispatcher.Dispatch(ctx, challenge, factorID)
+	if err != nil {
+		return fmt.Errorf("challenges: failed to dispatch factor %s: %w", factorID, err)
+	}
+
+	challenge.PendingFactor = factorID
+	challenge.PendingSecretHash = hashSecret(secret)
+	return m.repo.Update(ctx, challenge)
+}
+
+// Verify checks secret against the factor most recently dispatched by
+// StartFactor, and that ip/userAgent match the ones recorded when the
+// challenge was created. After MaxAttempts failures the challenge locks and
+// no further attempts are accepted even with the correct secret.
+func (m *Manager) Verify(ctx context.Context, challengeID, factorID, secret, ip, userAgent string) (bool, error) {
+	challenge, err := m.repo.Get(ctx, challengeID)
+	if err != nil {
+		return false, err
+	}
+	if err := assertChallengeOpen(challenge); err != nil {
+		return false, err
+	}
+	if challenge.PendingFactor == "" || challenge.PendingFactor != factorID {
+		return false, errors.New("challenges: factor does not match the one dispatched for this challenge")
+	}
+	if challenge.IP != ip || challenge.UserAgent != userAgent {
+		return false, errors.New("challenges: request fingerprint does not match the challenge")
+	}
+
+	match := subtle.ConstantTimeCompare([]byte(hashSecret(secret)), []byte(challenge.PendingSecretHash)) == 1
+	if !match {
+		challenge.Attempts++
+		if challenge.Attempts >= MaxAttempts {
+			challenge.Status = StatusLocked
+		}
+		if err := m.repo.Update(ctx, challenge); err != nil {
+			return false, err
+		}
+		return false, nil
+	}
+
+	challenge.Status = StatusApproved
+	if err := m.repo.Update(ctx, challenge); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Get returns the current state of challengeID.
+func (m *Manager) Get(ctx context.Context, challengeID string) (Challenge, error) {
+	return m.repo.Get(ctx, challengeID)
+}
+
+// ByTransactionRef returns the most recent challenge created for
+// transactionRef, for a transaction's HAL response to link to.
+func (m *Manager) ByTransactionRef(ctx context.Context, transactionRef string) (Challenge, error) {
+	return m.repo.FindByTransactionRef(ctx, transactionRef)
+}
+
+func assertFactorAllowed(challenge Challenge, factorID string) error {
+	for _, allowed := range challenge.AllowedFactors {
+		if allowed == factorID {
+			return nil
+		}
+	}
+	return fmt.Errorf("challenges: factor %s is not offered for this challenge", factorID)
+}
+
+func assertChallengeOpen(challenge Challenge) error {
+	switch {
+	case challenge.Status == StatusLocked:
+		return errors.New("challenges: challenge is locked after too many failed attempts")
+	case challenge.Status == StatusApproved:
+		return errors.New("challenges: challenge has already been approved")
+	case time.Now().After(challenge.ExpiresAt):
+		return errors.New("challenges: challenge has expired")
+	}
+	return nil
+}
+
+// hashSecret returns the hex-encoded SHA-256 of secret, so the repository
+// never stores a factor's plaintext OTP.
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func newChallengeID() string {
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	return "chal_" + hex.EncodeToString(buf)
+}
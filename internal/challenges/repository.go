@@ -0,0 +1,88 @@
+package challenges
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Repository persists Challenges across the create/start/verify lifecycle.
+type Repository interface {
+	Create(ctx context.Context, challenge Challenge) error
+	Get(ctx context.Context, id string) (Challenge, error)
+	Update(ctx context.Context, challenge Challenge) error
+	// FindByTransactionRef returns the most recently created Challenge for
+	// transactionRef, so a transaction's HAL response can link to it without
+	// the caller needing to already know the challenge ID.
+	FindByTransactionRef(ctx context.Context, transactionRef string) (Challenge, error)
+}
+
+// InMemoryRepository is a process-local Repository, suitable for a single
+// instance or for development; a multi-instance deployment needs a shared
+// backend (e.g. Redis or Postgres, following the same pattern as
+// repository.BlacklistRepository) so a challenge started on one instance can
+// be verified on another.
+type InMemoryRepository struct {
+	mu         sync.RWMutex
+	challenges map[string]Challenge
+}
+
+// NewInMemoryRepository creates an empty InMemoryRepository.
+func NewInMemoryRepository() *InMemoryRepository {
+	return &InMemoryRepository{challenges: make(map[string]Challenge)}
+}
+
+// Create stores challenge, keyed by its ID.
+func (r *InMemoryRepository) Create(ctx context.Context, challenge Challenge) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.challenges[challenge.ID] = challenge
+	return nil
+}
+
+// Get returns the challenge with the given id, or an error if it doesn't
+// exist.
+func (r *InMemoryRepository) Get(ctx context.Context, id string) (Challenge, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	challenge, ok := r.challenges[id]
+	if !ok {
+		return Challenge{}, fmt.Errorf("challenges: challenge %s not found", id)
+	}
+	return challenge, nil
+}
+
+// Update overwrites the stored challenge with the given ID.
+func (r *InMemoryRepository) Update(ctx context.Context, challenge Challenge) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.challenges[challenge.ID]; !ok {
+		return fmt.Errorf("challenges: challenge %s not found", challenge.ID)
+	}
+	r.challenges[challenge.ID] = challenge
+	return nil
+}
+
+// FindByTransactionRef scans for the most recently created challenge
+// matching transactionRef. A multi-instance deployment backed by a shared
+// store (see the Repository doc comment) should index this instead of
+// scanning.
+func (r *InMemoryRepository) FindByTransactionRef(ctx context.Context, transactionRef string) (Challenge, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var found Challenge
+	var ok bool
+	for _, challenge := range r.challenges {
+		if challenge.TransactionRef != transactionRef {
+			continue
+		}
+		if !ok || challenge.CreatedAt.After(found.CreatedAt) {
+			found, ok = challenge, true
+		}
+	}
+	if !ok {
+		return Challenge{}, fmt.Errorf("challenges: no challenge found for transaction %s", transactionRef)
+	}
+	return found, nil
+}
@@ -0,0 +1,51 @@
+package events
+
+import (
+	"context"
+	"log"
+)
+
+// emitJob is one fire-and-forget publish request queued on an AsyncEmitter.
+type emitJob struct {
+	subject string
+	event   Event
+}
+
+// AsyncEmitter decouples event publishing from the request path that
+// triggers it: Emit enqueues the event and returns immediately, while a
+// bounded pool of background workers does the actual Publisher.Publish
+// call, so a slow or unreachable consumer never adds latency to (or fails)
+// the API response that produced the event.
+type AsyncEmitter struct {
+	publisher Publisher
+	jobs      chan emitJob
+}
+
+// NewAsyncEmitter starts workers goroutines draining a queue of depth
+// queueDepth, each calling publisher.Publish for every job handed to Emit.
+func NewAsyncEmitter(publisher Publisher, workers, queueDepth int) *AsyncEmitter {
+	e := &AsyncEmitter{publisher: publisher, jobs: make(chan emitJob, queueDepth)}
+	for i := 0; i < workers; i++ {
+		go e.run()
+	}
+	return e
+}
+
+func (e *AsyncEmitter) run() {
+	for job := range e.jobs {
+		if err := e.publisher.Publish(context.Background(), job.subject, job.event); err != nil {
+			log.Printf("events: failed to emit %s event: %v", job.subject, err)
+		}
+	}
+}
+
+// Emit queues event for background delivery to subject. If the queue is
+// already full, the event is dropped and logged rather than blocking the
+// caller.
+func (e *AsyncEmitter) Emit(subject string, event Event) {
+	select {
+	case e.jobs <- emitJob{subject: subject, event: event}:
+	default:
+		log.Printf("events: emitter queue full, dropping %s event", subject)
+	}
+}
@@ -0,0 +1,44 @@
+// Package events provides a broker-agnostic publish/subscribe abstraction
+// used to run fraud evaluation asynchronously against a stream (NATS
+// JetStream or Kafka) instead of inline on the request path, and to emit
+// fraud decisions for downstream consumers.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Event is the envelope published to every subject this package deals with,
+// e.g. "transaction.submitted" or "fraud.decisions".
+type Event struct {
+	Type       string          `json:"type"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// NewEvent marshals data into an Event of the given type.
+func NewEvent(eventType string, data interface{}) (Event, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return Event{}, err
+	}
+	return Event{Type: eventType, OccurredAt: time.Now(), Data: raw}, nil
+}
+
+// Publisher publishes events to a subject on the underlying broker.
+type Publisher interface {
+	Publish(ctx context.Context, subject string, event Event) error
+	Close() error
+}
+
+// Handler processes one event consumed from a subject. Returning an error
+// leaves the event unacknowledged so the broker redelivers it.
+type Handler func(ctx context.Context, event Event) error
+
+// Subscriber consumes events from a subject, invoking handler for each one.
+type Subscriber interface {
+	Subscribe(ctx context.Context, subject string, handler Handler) error
+	Close() error
+}
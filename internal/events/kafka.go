@@ -0,0 +1,86 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	kafka "github.com/segmentio/kafka-go"
+)
+
+// KafkaBroker implements Publisher and Subscriber over Kafka topics,
+// treating each event subject as a topic name.
+type KafkaBroker struct {
+	brokers []string
+	groupID string
+	writer  *kafka.Writer
+	readers []*kafka.Reader
+}
+
+// NewKafkaBroker creates a broker that talks to brokers (host:port list),
+// consuming with consumer group groupID so multiple instances share the
+// partitions of a topic instead of each reading every message.
+func NewKafkaBroker(brokers []string, groupID string) *KafkaBroker {
+	return &KafkaBroker{
+		brokers: brokers,
+		groupID: groupID,
+		writer:  &kafka.Writer{Addr: kafka.TCP(brokers...), Balancer: &kafka.LeastBytes{}},
+	}
+}
+
+// Publish writes event as JSON to the topic named subject.
+func (b *KafkaBroker) Publish(ctx context.Context, subject string, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("events: failed to marshal event for %s: %w", subject, err)
+	}
+	if err := b.writer.WriteMessages(ctx, kafka.Message{Topic: subject, Value: payload}); err != nil {
+		return fmt.Errorf("events: failed to publish to topic %s: %w", subject, err)
+	}
+	return nil
+}
+
+// Subscribe consumes the topic named subject under the broker's consumer
+// group, invoking handler for each message and committing the offset only
+// once handler succeeds.
+func (b *KafkaBroker) Subscribe(ctx context.Context, subject string, handler Handler) error {
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers: b.brokers,
+		GroupID: b.groupID,
+		Topic:   subject,
+	})
+	b.readers = append(b.readers, reader)
+
+	go func() {
+		for {
+			msg, err := reader.FetchMessage(ctx)
+			if err != nil {
+				return // context cancelled or reader closed
+			}
+
+			var event Event
+			if err := json.Unmarshal(msg.Value, &event); err != nil {
+				continue // drop unparseable messages rather than wedge the consumer
+			}
+			if err := handler(ctx, event); err != nil {
+				continue // at-least-once: skip the commit so it's redelivered on restart
+			}
+			reader.CommitMessages(ctx, msg)
+		}
+	}()
+	return nil
+}
+
+// Close shuts down the writer and every reader created via Subscribe.
+func (b *KafkaBroker) Close() error {
+	var firstErr error
+	if err := b.writer.Close(); err != nil {
+		firstErr = err
+	}
+	for _, reader := range b.readers {
+		if err := reader.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,63 @@
+package events
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// ConsumerMetrics tracks per-subject consumer health so operators can watch
+// for a stream falling behind. Values are updated by whatever Subscriber
+// implementation is wrapped with Instrument.
+type ConsumerMetrics struct {
+	processed           atomic.Int64
+	errors              atomic.Int64
+	lastProcessingNanos atomic.Int64
+	lastEventAt         atomic.Int64 // unix nanos
+}
+
+// Snapshot is the point-in-time view of ConsumerMetrics exposed over the
+// admin API.
+type Snapshot struct {
+	Processed          int64         `json:"processed"`
+	Errors             int64         `json:"errors"`
+	LastProcessingTime time.Duration `json:"last_processing_time_ns"`
+	LagSinceLastEvent  time.Duration `json:"lag_since_last_event_ns"`
+}
+
+// record stores the outcome of handling one event.
+func (m *ConsumerMetrics) record(d time.Duration, err error) {
+	m.processed.Add(1)
+	if err != nil {
+		m.errors.Add(1)
+	}
+	m.lastProcessingNanos.Store(int64(d))
+	m.lastEventAt.Store(time.Now().UnixNano())
+}
+
+// Snapshot reports current counters. LagSinceLastEvent is how long it has
+// been since any event was last processed, a simple proxy for consumer lag
+// when the broker client doesn't expose offset lag directly.
+func (m *ConsumerMetrics) Snapshot() Snapshot {
+	var lag time.Duration
+	if last := m.lastEventAt.Load(); last != 0 {
+		lag = time.Since(time.Unix(0, last))
+	}
+	return Snapshot{
+		Processed:          m.processed.Load(),
+		Errors:             m.errors.Load(),
+		LastProcessingTime: time.Duration(m.lastProcessingNanos.Load()),
+		LagSinceLastEvent:  lag,
+	}
+}
+
+// Instrument wraps handler so every invocation updates m with its processing
+// latency and outcome.
+func (m *ConsumerMetrics) Instrument(handler Handler) Handler {
+	return func(ctx context.Context, event Event) error {
+		start := time.Now()
+		err := handler(ctx, event)
+		m.record(time.Since(start), err)
+		return err
+	}
+}
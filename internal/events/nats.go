@@ -0,0 +1,105 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// NATSBroker implements both Publisher and Subscriber over a NATS JetStream
+// connection, publishing to and consuming from durable streams so events
+// survive a consumer restart.
+type NATSBroker struct {
+	conn *nats.Conn
+	js   jetstream.JetStream
+}
+
+// NewNATSBroker connects to url and wraps the connection's JetStream context.
+func NewNATSBroker(url string) (*NATSBroker, error) {
+	conn, err := nats.Connect(url)
+	if err != nil {
+		return nil, fmt.Errorf("events: failed to connect to NATS at %s: %w", url, err)
+	}
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("events: failed to initialize JetStream: %w", err)
+	}
+	return &NATSBroker{conn: conn, js: js}, nil
+}
+
+// Publish sends event as JSON to subject.
+func (b *NATSBroker) Publish(ctx context.Context, subject string, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("events: failed to marshal event for %s: %w", subject, err)
+	}
+	if _, err := b.js.Publish(ctx, subject, payload); err != nil {
+		return fmt.Errorf("events: failed to publish to %s: %w", subject, err)
+	}
+	return nil
+}
+
+// Subscribe creates (or reuses) a durable consumer named after subject and
+// invokes handler for every message, acking only on success so a failing
+// handler causes redelivery.
+func (b *NATSBroker) Subscribe(ctx context.Context, subject string, handler Handler) error {
+	streamName := streamNameFor(subject)
+	stream, err := b.js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:     streamName,
+		Subjects: []string{subject},
+	})
+	if err != nil {
+		return fmt.Errorf("events: failed to create stream for %s: %w", subject, err)
+	}
+
+	consumer, err := stream.CreateOrUpdateConsumer(ctx, jetstream.ConsumerConfig{
+		Durable:       durableNameFor(subject),
+		AckPolicy:     jetstream.AckExplicitPolicy,
+		FilterSubject: subject,
+	})
+	if err != nil {
+		return fmt.Errorf("events: failed to create consumer for %s: %w", subject, err)
+	}
+
+	_, err = consumer.Consume(func(msg jetstream.Msg) {
+		var event Event
+		if err := json.Unmarshal(msg.Data(), &event); err != nil {
+			msg.Nak()
+			return
+		}
+		if err := handler(ctx, event); err != nil {
+			msg.Nak()
+			return
+		}
+		msg.Ack()
+	})
+	if err != nil {
+		return fmt.Errorf("events: failed to start consuming %s: %w", subject, err)
+	}
+	return nil
+}
+
+// Close drains and closes the underlying NATS connection.
+func (b *NATSBroker) Close() error {
+	b.conn.Close()
+	return nil
+}
+
+func streamNameFor(subject string) string  { return "FRAUD_" + sanitizeSubject(subject) }
+func durableNameFor(subject string) string { return "fraud-" + sanitizeSubject(subject) }
+
+func sanitizeSubject(subject string) string {
+	out := make([]rune, 0, len(subject))
+	for _, r := range subject {
+		if r == '.' || r == '-' {
+			out = append(out, '_')
+			continue
+		}
+		out = append(out, r)
+	}
+	return string(out)
+}
@@ -0,0 +1,209 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// OutboxRecord is a row in the outbox table awaiting delivery.
+type OutboxRecord struct {
+	ID          int64
+	Destination string
+	Event       Event
+	CreatedAt   time.Time
+	Attempts    int
+}
+
+// OutboxStore persists events destined for an external system (here, the
+// transaction service) so delivery can be retried at-least-once even if the
+// process crashes mid-delivery.
+type OutboxStore interface {
+	Enqueue(ctx context.Context, destination string, event Event) error
+	FetchPending(ctx context.Context, limit int) ([]OutboxRecord, error)
+	// FetchBetween returns every record (delivered or not) created in
+	// [from, to], oldest first, for Dispatcher.Redeliver to resend.
+	FetchBetween(ctx context.Context, from, to time.Time) ([]OutboxRecord, error)
+	MarkDelivered(ctx context.Context, id int64) error
+	MarkFailed(ctx context.Context, id int64) error
+}
+
+// PostgresOutboxStore implements OutboxStore against a
+// fraud_event_outbox table:
+//
+//	CREATE TABLE fraud_event_outbox (
+//		id          BIGSERIAL PRIMARY KEY,
+//		destination TEXT NOT NULL,
+//		event_type  TEXT NOT NULL,
+//		payload     JSONB NOT NULL,
+//		created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+//		delivered_at TIMESTAMPTZ,
+//		attempts    INT NOT NULL DEFAULT 0
+//	);
+type PostgresOutboxStore struct {
+	db *sql.DB
+}
+
+// NewPostgresOutboxStore wraps an existing *sql.DB; the service is expected
+// to own connection pooling and migrations.
+func NewPostgresOutboxStore(db *sql.DB) *PostgresOutboxStore {
+	return &PostgresOutboxStore{db: db}
+}
+
+// Enqueue inserts event for delivery to destination.
+func (s *PostgresOutboxStore) Enqueue(ctx context.Context, destination string, event Event) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO fraud_event_outbox (destination, event_type, payload) VALUES ($1, $2, $3)`,
+		destination, event.Type, event.Data,
+	)
+	if err != nil {
+		return fmt.Errorf("events: failed to enqueue outbox record: %w", err)
+	}
+	return nil
+}
+
+// FetchPending returns up to limit undelivered records, oldest first.
+func (s *PostgresOutboxStore) FetchPending(ctx context.Context, limit int) ([]OutboxRecord, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, destination, event_type, payload, created_at, attempts
+		 FROM fraud_event_outbox
+		 WHERE delivered_at IS NULL
+		 ORDER BY created_at ASC
+		 LIMIT $1`,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("events: failed to fetch pending outbox records: %w", err)
+	}
+	defer rows.Close()
+	return scanOutboxRows(rows)
+}
+
+// FetchBetween returns every record created in [from, to], oldest first,
+// regardless of whether it was already delivered, for redelivery via
+// Dispatcher.Redeliver.
+func (s *PostgresOutboxStore) FetchBetween(ctx context.Context, from, to time.Time) ([]OutboxRecord, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, destination, event_type, payload, created_at, attempts
+		 FROM fraud_event_outbox
+		 WHERE created_at >= $1 AND created_at <= $2
+		 ORDER BY created_at ASC`,
+		from, to,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("events: failed to fetch outbox records between %s and %s: %w", from, to, err)
+	}
+	defer rows.Close()
+	return scanOutboxRows(rows)
+}
+
+func scanOutboxRows(rows *sql.Rows) ([]OutboxRecord, error) {
+	var records []OutboxRecord
+	for rows.Next() {
+		var r OutboxRecord
+		if err := rows.Scan(&r.ID, &r.Destination, &r.Event.Type, &r.Event.Data, &r.CreatedAt, &r.Attempts); err != nil {
+			return nil, fmt.Errorf("events: failed to scan outbox record: %w", err)
+		}
+		records = append(records, r)
+	}
+	return records, rows.Err()
+}
+
+// MarkDelivered records a successful delivery.
+func (s *PostgresOutboxStore) MarkDelivered(ctx context.Context, id int64) error {
+	_, err := s.db.ExecContext(ctx, `UPDATE fraud_event_outbox SET delivered_at = now() WHERE id = $1`, id)
+	return err
+}
+
+// MarkFailed increments the attempt counter after a failed delivery.
+func (s *PostgresOutboxStore) MarkFailed(ctx context.Context, id int64) error {
+	_, err := s.db.ExecContext(ctx, `UPDATE fraud_event_outbox SET attempts = attempts + 1 WHERE id = $1`, id)
+	return err
+}
+
+// Dispatcher periodically drains an OutboxStore, POSTing each pending
+// record's payload to its destination URL, guaranteeing at-least-once
+// delivery to the transaction service even across process restarts.
+type Dispatcher struct {
+	store      OutboxStore
+	httpClient *http.Client
+	batchSize  int
+}
+
+// NewDispatcher creates a Dispatcher draining store in batches of batchSize.
+func NewDispatcher(store OutboxStore, batchSize int) *Dispatcher {
+	return &Dispatcher{store: store, httpClient: http.DefaultClient, batchSize: batchSize}
+}
+
+// Run drains the outbox every interval until ctx is cancelled.
+func (d *Dispatcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.drainOnce(ctx)
+		}
+	}
+}
+
+// Redeliver resends every record created in [from, to], regardless of
+// whether it was already delivered, for operator-triggered backfills (e.g.
+// a downstream consumer that lost data and needs a window of events
+// replayed). It returns how many records were redelivered successfully;
+// a failed redelivery is skipped rather than aborting the rest of the
+// window.
+func (d *Dispatcher) Redeliver(ctx context.Context, from, to time.Time) (int, error) {
+	records, err := d.store.FetchBetween(ctx, from, to)
+	if err != nil {
+		return 0, err
+	}
+
+	redelivered := 0
+	for _, record := range records {
+		if err := d.deliver(ctx, record); err != nil {
+			continue
+		}
+		redelivered++
+	}
+	return redelivered, nil
+}
+
+func (d *Dispatcher) drainOnce(ctx context.Context) {
+	records, err := d.store.FetchPending(ctx, d.batchSize)
+	if err != nil {
+		return
+	}
+	for _, record := range records {
+		if err := d.deliver(ctx, record); err != nil {
+			d.store.MarkFailed(ctx, record.ID)
+			continue
+		}
+		d.store.MarkDelivered(ctx, record.ID)
+	}
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, record OutboxRecord) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, record.Destination, bytes.NewReader(record.Event.Data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-Type", record.Event.Type)
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("events: destination %s responded with status %d", record.Destination, resp.StatusCode)
+	}
+	return nil
+}
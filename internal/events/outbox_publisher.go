@@ -0,0 +1,41 @@
+package events
+
+import (
+	"context"
+	"fmt"
+)
+
+// OutboxEnqueuingPublisher wraps a Publisher so every published event is
+// also queued in outbox under destination, giving it a second, durable
+// delivery path that a Dispatcher can redeliver from later (see
+// Dispatcher.Redeliver and the /fraud/admin/events/replay route). If outbox
+// or destination is empty it simply delegates to next.
+type OutboxEnqueuingPublisher struct {
+	next        Publisher
+	outbox      OutboxStore
+	destination string
+}
+
+// NewOutboxEnqueuingPublisher wraps next, additionally enqueuing every
+// published event into outbox under destination.
+func NewOutboxEnqueuingPublisher(next Publisher, outbox OutboxStore, destination string) *OutboxEnqueuingPublisher {
+	return &OutboxEnqueuingPublisher{next: next, outbox: outbox, destination: destination}
+}
+
+// Publish delegates to next, then enqueues event into outbox when both are
+// configured.
+func (p *OutboxEnqueuingPublisher) Publish(ctx context.Context, subject string, event Event) error {
+	if err := p.next.Publish(ctx, subject, event); err != nil {
+		return err
+	}
+	if p.outbox == nil || p.destination == "" {
+		return nil
+	}
+	if err := p.outbox.Enqueue(ctx, p.destination, event); err != nil {
+		return fmt.Errorf("events: failed to enqueue event for replay: %w", err)
+	}
+	return nil
+}
+
+// Close closes the wrapped Publisher.
+func (p *OutboxEnqueuingPublisher) Close() error { return p.next.Close() }
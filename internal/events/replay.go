@@ -0,0 +1,59 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// HistoricalEventSource yields previously published "transaction.submitted"
+// events for replay, e.g. reading a JetStream/Kafka topic from its earliest
+// offset instead of the live tail a Subscriber would normally consume.
+type HistoricalEventSource interface {
+	// Each fetches every historical event in order, calling visit for each
+	// one. It returns once the source is exhausted or visit returns an error.
+	Each(ctx context.Context, subject string, visit func(Event) error) error
+}
+
+// Rescorer re-evaluates a transaction payload against the current ruleset,
+// e.g. services.FraudService.CheckTransaction.
+type Rescorer func(ctx context.Context, transactionData map[string]interface{}) (interface{}, error)
+
+// ReplayResult pairs a historical event with the decision the current
+// ruleset would have produced for it, for backtesting rule/model changes
+// against recorded traffic.
+type ReplayResult struct {
+	OriginalEvent Event       `json:"original_event"`
+	Rescored      interface{} `json:"rescored"`
+	Err           string      `json:"error,omitempty"`
+}
+
+// Replay re-scores every "transaction.submitted" event read from source
+// against rescorer, returning one ReplayResult per event. A rescoring
+// failure is recorded on the result rather than aborting the run, so one bad
+// historical record doesn't stop the backtest.
+func Replay(ctx context.Context, source HistoricalEventSource, subject string, rescorer Rescorer) ([]ReplayResult, error) {
+	var results []ReplayResult
+
+	err := source.Each(ctx, subject, func(event Event) error {
+		var transactionData map[string]interface{}
+		if err := json.Unmarshal(event.Data, &transactionData); err != nil {
+			results = append(results, ReplayResult{OriginalEvent: event, Err: fmt.Sprintf("failed to decode event payload: %v", err)})
+			return nil
+		}
+
+		decision, err := rescorer(ctx, transactionData)
+		if err != nil {
+			results = append(results, ReplayResult{OriginalEvent: event, Err: err.Error()})
+			return nil
+		}
+
+		results = append(results, ReplayResult{OriginalEvent: event, Rescored: decision})
+		return nil
+	})
+	if err != nil {
+		return results, fmt.Errorf("events: replay aborted: %w", err)
+	}
+
+	return results, nil
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync/atomic"
 	"time"
 
+	"github.com/kodra-pay/fraud-service/internal/cases"
 	"github.com/kodra-pay/fraud-service/internal/repository"
 )
 
@@ -18,16 +20,81 @@ type FraudDetector interface {
 
 // RuleBasedFraudDetector implements FraudDetector using a set of predefined rules.
 type RuleBasedFraudDetector struct {
-	repo  repository.FraudDataRepository
-	rules []FraudRule
+	repo           repository.FraudDataRepository
+	velocityRepo   repository.VelocityRepository
+	blacklist      repository.BlacklistRepository
+	caseManager    *cases.CaseManager
+	ipEnricher     repository.IPEnricher
+	deviceEnricher repository.DeviceEnricher
+	rules          atomic.Pointer[[]FraudRule]
 }
 
 // NewRuleBasedFraudDetector creates a new instance of RuleBasedFraudDetector.
+// Rules with a VelocityKey fall back to an in-memory velocity repository
+// until WithVelocityRepository wires in a shared backend, and the IP/device/
+// card-bin blacklist starts out empty until analyst-confirmed fraud backfills
+// it via WithCaseManager.
 func NewRuleBasedFraudDetector(repo repository.FraudDataRepository, rules []FraudRule) *RuleBasedFraudDetector {
 	if len(rules) == 0 {
 		rules = DefaultRules()
 	}
-	return &RuleBasedFraudDetector{repo: repo, rules: rules}
+	d := &RuleBasedFraudDetector{
+		repo:         repo,
+		velocityRepo: repository.NewInMemoryVelocityRepository(),
+		blacklist:    repository.NewInMemoryBlacklistRepository(),
+	}
+	d.rules.Store(&rules)
+	return d
+}
+
+// WithVelocityRepository swaps in a shared velocity backend (e.g. Redis) so
+// velocity aggregates are consistent across instances.
+func (d *RuleBasedFraudDetector) WithVelocityRepository(velocityRepo repository.VelocityRepository) *RuleBasedFraudDetector {
+	d.velocityRepo = velocityRepo
+	return d
+}
+
+// WithBlacklistRepository swaps in a shared blacklist backend so entries
+// added by the analyst case workflow are consulted across instances.
+func (d *RuleBasedFraudDetector) WithBlacklistRepository(blacklist repository.BlacklistRepository) *RuleBasedFraudDetector {
+	d.blacklist = blacklist
+	return d
+}
+
+// WithCaseManager enables the analyst case workflow: every non-approve
+// decision from CheckTransaction auto-opens a case via caseManager.
+func (d *RuleBasedFraudDetector) WithCaseManager(caseManager *cases.CaseManager) *RuleBasedFraudDetector {
+	d.caseManager = caseManager
+	return d
+}
+
+// WithIPEnricher enables IP geo/ASN enrichment: CheckTransaction resolves
+// transactionData["origin"] and surfaces the result as the "ip.*" attributes
+// rules can match against, and as the location geo-velocity compares future
+// transactions to.
+func (d *RuleBasedFraudDetector) WithIPEnricher(ipEnricher repository.IPEnricher) *RuleBasedFraudDetector {
+	d.ipEnricher = ipEnricher
+	return d
+}
+
+// WithDeviceEnricher enables device-fingerprint enrichment: CheckTransaction
+// scores transactionData["device_fingerprint"] and surfaces the result as
+// the "device.*" attributes rules can match against.
+func (d *RuleBasedFraudDetector) WithDeviceEnricher(deviceEnricher repository.DeviceEnricher) *RuleBasedFraudDetector {
+	d.deviceEnricher = deviceEnricher
+	return d
+}
+
+// SetRules atomically swaps the active rule set, e.g. when a RuleLoader picks
+// up a change to the rules file. In-flight CheckTransaction calls finish
+// evaluating against whichever rule set they already loaded.
+func (d *RuleBasedFraudDetector) SetRules(rules []FraudRule) {
+	d.rules.Store(&rules)
+}
+
+// Rules returns the currently active rule set.
+func (d *RuleBasedFraudDetector) Rules() []FraudRule {
+	return *d.rules.Load()
 }
 
 // CheckTransaction performs fraud checks on transaction data.
@@ -38,7 +105,52 @@ func (d *RuleBasedFraudDetector) CheckTransaction(ctx context.Context, transacti
 	}
 	var totalScore float64
 
-	for _, rule := range d.rules {
+	// Enrichment results are written to enrichedData, a copy-on-write clone
+	// of transactionData, never the caller's map directly: transactionData
+	// may be read concurrently by other ensemble scorers dispatched against
+	// the same map (see ScorerRegistry.Dispatch), and mutating it here would
+	// race with those reads.
+	enrichedData := transactionData
+	cloned := false
+	ensureCloned := func() {
+		if !cloned {
+			enrichedData = make(map[string]interface{}, len(transactionData)+1)
+			for k, v := range transactionData {
+				enrichedData[k] = v
+			}
+			cloned = true
+		}
+	}
+
+	var ipInfo repository.IPInfo
+	var haveIPInfo bool
+	if origin, ok := transactionData["origin"].(string); ok && origin != "" && d.ipEnricher != nil {
+		info, err := d.ipEnricher.Enrich(ctx, origin)
+		if err != nil {
+			return FraudDecision{}, fmt.Errorf("failed to enrich origin IP: %w", err)
+		}
+		ipInfo, haveIPInfo = info, true
+		ensureCloned()
+		enrichedData["ip"] = info.Attrs()
+	}
+
+	if fingerprint, ok := transactionData["device_fingerprint"].(string); ok && fingerprint != "" && d.deviceEnricher != nil {
+		info, err := d.deviceEnricher.Enrich(ctx, fingerprint)
+		if err != nil {
+			return FraudDecision{}, fmt.Errorf("failed to enrich device fingerprint: %w", err)
+		}
+		ensureCloned()
+		enrichedData["device"] = info.Attrs()
+	}
+
+	// incrementedVelocityKeys tracks which velocity keys this call has
+	// already incremented, so rules that share a VelocityKey and field
+	// value (e.g. HIGH_VELOCITY_CUSTOMER and BURST_VELOCITY_CUSTOMER both
+	// keying on customer_id) increment the counter once per transaction,
+	// not once per rule, and just each Count their own window.
+	incrementedVelocityKeys := make(map[string]struct{})
+
+	for _, rule := range d.Rules() {
 		if !rule.Enabled {
 			continue
 		}
@@ -47,25 +159,60 @@ func (d *RuleBasedFraudDetector) CheckTransaction(ctx context.Context, transacti
 		var isTriggered bool
 		var err error
 
-		switch rule.ID {
-		case "HIGH_VELOCITY_CUSTOMER":
-			if customerID, ok := transactionData["customer_id"].(string); ok {
-				history, repoErr := d.repo.GetTransactionHistory(ctx, customerID, 24*time.Hour)
-				if repoErr != nil {
-					return FraudDecision{}, fmt.Errorf("failed to get transaction history for rule %s: %w", rule.ID, repoErr)
+		switch {
+		case rule.VelocityKey != "":
+			fieldValue, ok := transactionData[rule.VelocityKey].(string)
+			if !ok || fieldValue == "" {
+				continue
+			}
+			velocityKey := fmt.Sprintf("%s:%s", rule.VelocityKey, fieldValue)
+			if _, already := incrementedVelocityKeys[velocityKey]; !already {
+				amount, _ := transactionData["amount"].(float64)
+				merchantID, _ := transactionData["merchant_id"].(string)
+				if repoErr := d.velocityRepo.Increment(ctx, velocityKey, time.Now(), amount, merchantID); repoErr != nil {
+					return FraudDecision{}, fmt.Errorf("failed to increment velocity for rule %s: %w", rule.ID, repoErr)
 				}
-				isTriggered = float64(len(history)) > rule.Threshold
+				incrementedVelocityKeys[velocityKey] = struct{}{}
 			}
-		case "SUSPICIOUS_IP_ORIGIN":
+			count, repoErr := d.velocityRepo.Count(ctx, velocityKey, rule.Window, rule.Aggregation)
+			if repoErr != nil {
+				return FraudDecision{}, fmt.Errorf("failed to count velocity for rule %s: %w", rule.ID, repoErr)
+			}
+			isTriggered = count > rule.Threshold
+		case rule.ID == "SUSPICIOUS_IP_ORIGIN":
 			if origin, ok := transactionData["origin"].(string); ok {
 				ipData, repoErr := d.repo.GetIPData(ctx, origin)
 				if repoErr != nil {
 					return FraudDecision{}, fmt.Errorf("failed to get IP data for rule %s: %w", rule.ID, repoErr)
 				}
-				isTriggered = (ipData != nil && ipData["is_vpn"] == true) || origin == "suspicious_ip" // Placeholder logic
+				blacklisted, repoErr := d.blacklist.Contains(ctx, repository.BlacklistKindIP, origin)
+				if repoErr != nil {
+					return FraudDecision{}, fmt.Errorf("failed to check IP blacklist for rule %s: %w", rule.ID, repoErr)
+				}
+				isTriggered = (ipData != nil && ipData["is_vpn"] == true) || origin == "suspicious_ip" || blacklisted // Placeholder logic
+			}
+		case rule.ID == "GEO_VELOCITY_IMPOSSIBLE_TRAVEL":
+			if haveIPInfo {
+				customerID, _ := transactionData["customer_id"].(string)
+				if customerID != "" {
+					history, repoErr := d.repo.GetTransactionHistory(ctx, customerID, rule.Window)
+					if repoErr != nil {
+						return FraudDecision{}, fmt.Errorf("failed to get transaction history for rule %s: %w", rule.ID, repoErr)
+					}
+					for _, prior := range history {
+						if prior.IPCountry == "" || prior.IPCountry == ipInfo.Country {
+							continue
+						}
+						distanceKM := haversineKM(prior.Latitude, prior.Longitude, ipInfo.Latitude, ipInfo.Longitude)
+						if distanceKM > rule.Threshold {
+							isTriggered = true
+							break
+						}
+					}
+				}
 			}
 		default:
-			isTriggered, err = rule.Predicate(transactionData)
+			isTriggered, err = rule.Predicate(enrichedData)
 			if err != nil {
 				return FraudDecision{}, fmt.Errorf("error evaluating rule %s: %w", rule.ID, err)
 			}
@@ -73,14 +220,22 @@ func (d *RuleBasedFraudDetector) CheckTransaction(ctx context.Context, transacti
 
 
 		if isTriggered {
+			if rule.Shadow {
+				// Shadow rules are surfaced for audit but never move the
+				// decision, so a rule can be tuned against live traffic
+				// before it's trusted.
+				decision.Reasons = append(decision.Reasons, fmt.Sprintf("[shadow] %s (score=%.1f)", rule.Description, rule.ScoreImpact))
+				continue
+			}
+
 			totalScore += rule.ScoreImpact
 			decision.Reasons = append(decision.Reasons, rule.Description)
 			// Apply immediate decision if rule dictates
 			if rule.Decision == "deny" {
 				decision.Decision = "deny"
 				break // Stop evaluating if denied
-			} else if rule.Decision == "flag" && decision.Decision != "deny" {
-				decision.Decision = "flag"
+			} else if rule.Decision == "challenge" && decision.Decision != "deny" {
+				decision.Decision = "challenge"
 			}
 		}
 	}
@@ -92,10 +247,33 @@ func (d *RuleBasedFraudDetector) CheckTransaction(ctx context.Context, transacti
 		if totalScore >= 100 { // Example high risk threshold
 			decision.Decision = "deny"
 		} else if totalScore >= 50 { // Example medium risk threshold
-			decision.Decision = "flag"
+			decision.Decision = "challenge"
+		}
+	}
+
+	if haveIPInfo {
+		if customerID, ok := transactionData["customer_id"].(string); ok && customerID != "" {
+			record := repository.TransactionRecord{
+				Timestamp: time.Now(),
+				IPCountry: ipInfo.Country,
+				Latitude:  ipInfo.Latitude,
+				Longitude: ipInfo.Longitude,
+			}
+			if amount, ok := transactionData["amount"].(float64); ok {
+				record.Amount = amount
+			}
+			if err := d.repo.RecordTransactionLocation(ctx, customerID, record); err != nil {
+				decision.Reasons = append(decision.Reasons, fmt.Sprintf("geo-velocity: failed to record transaction location: %v", err))
+			}
 		}
 	}
 
+	if d.caseManager != nil && decision.Decision != "approve" {
+		reference, _ := transactionData["reference"].(string)
+		if _, err := d.caseManager.OpenCase(ctx, reference, enrichedData, decision.OverallScore, decision.Reasons); err != nil {
+			decision.Reasons = append(decision.Reasons, fmt.Sprintf("case management: %v", err))
+		}
+	}
 
 	return decision, nil
 }
@@ -0,0 +1,44 @@
+// Package dsl implements the small expression language used to write
+// declarative fraud rules, e.g.:
+//
+//	amount > 1000 and currency == "USD"
+//	velocity.customer_id.1h.count > 5
+//	ip.country in ["RU", "KP"]
+//	device.fingerprint matches /^anon-/
+//
+// It supports arithmetic, comparison, boolean logic, `in [...]`,
+// `startswith`, `matches /regex/`, and dotted attribute paths. It is a
+// hand-written Pratt parser rather than an embedded general-purpose engine,
+// since the rule grammar this service needs is small and fixed.
+package dsl
+
+import "fmt"
+
+// Program is a compiled expression ready to be run against an Env.
+type Program struct {
+	source string
+	root   node
+}
+
+// Compile parses and validates expr, returning a reusable Program. Compile
+// errors are returned as-is so callers (e.g. a rules-file loader) can report
+// exactly which rule failed to parse.
+func Compile(expr string) (*Program, error) {
+	root, err := parse(expr)
+	if err != nil {
+		return nil, fmt.Errorf("dsl: failed to compile %q: %w", expr, err)
+	}
+	return &Program{source: expr, root: root}, nil
+}
+
+// Source returns the original expression text.
+func (p *Program) Source() string { return p.source }
+
+// Run evaluates the program against env and coerces the result to a bool.
+func (p *Program) Run(env Env) (bool, error) {
+	v, err := p.root.eval(env)
+	if err != nil {
+		return false, fmt.Errorf("dsl: failed to evaluate %q: %w", p.source, err)
+	}
+	return truthy(v), nil
+}
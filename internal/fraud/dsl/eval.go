@@ -0,0 +1,238 @@
+package dsl
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Env is the attribute namespace an expression evaluates against, e.g.
+// {"tx": transactionData, "ip": ipData, "velocity": aggregates}. Attribute
+// paths like "tx.origin" or "velocity.customer_id.1h.count" are resolved by
+// walking nested maps one dotted segment at a time.
+type Env map[string]interface{}
+
+func (e Env) resolve(dotted string) (interface{}, bool) {
+	segments := strings.Split(dotted, ".")
+	var current interface{} = map[string]interface{}(e)
+	for _, seg := range segments {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		current, ok = m[seg]
+		if !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+type literal struct{ value interface{} }
+
+func (l *literal) eval(_ Env) (interface{}, error) { return l.value, nil }
+
+type path struct{ dotted string }
+
+func (p *path) eval(env Env) (interface{}, error) {
+	v, ok := env.resolve(p.dotted)
+	if !ok {
+		return nil, nil
+	}
+	return v, nil
+}
+
+type list struct{ items []node }
+
+func (l *list) eval(env Env) (interface{}, error) {
+	values := make([]interface{}, len(l.items))
+	for i, item := range l.items {
+		v, err := item.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+type boolOp struct {
+	op          string
+	left, right node
+}
+
+func (b *boolOp) eval(env Env) (interface{}, error) {
+	left, err := b.left.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	if b.op == "and" && !truthy(left) {
+		return false, nil
+	}
+	if b.op == "or" && truthy(left) {
+		return true, nil
+	}
+	right, err := b.right.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	return truthy(right), nil
+}
+
+type notOp struct{ operand node }
+
+func (n *notOp) eval(env Env) (interface{}, error) {
+	v, err := n.operand.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	return !truthy(v), nil
+}
+
+type arithOp struct {
+	op          string
+	left, right node
+}
+
+func (a *arithOp) eval(env Env) (interface{}, error) {
+	left, err := a.left.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	right, err := a.right.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	lf, lok := asFloat(left)
+	rf, rok := asFloat(right)
+	if !lok || !rok {
+		return nil, fmt.Errorf("arithmetic operator %q requires numeric operands", a.op)
+	}
+	switch a.op {
+	case "+":
+		return lf + rf, nil
+	case "-":
+		return lf - rf, nil
+	case "*":
+		return lf * rf, nil
+	case "/":
+		if rf == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		return lf / rf, nil
+	case "%":
+		if rf == 0 {
+			return nil, fmt.Errorf("modulo by zero")
+		}
+		return float64(int64(lf) % int64(rf)), nil
+	default:
+		return nil, fmt.Errorf("unknown arithmetic operator %q", a.op)
+	}
+}
+
+type compareOp struct {
+	op          string
+	left, right node
+}
+
+func (c *compareOp) eval(env Env) (interface{}, error) {
+	left, err := c.left.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	right, err := c.right.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	switch c.op {
+	case "==":
+		return equal(left, right), nil
+	case "!=":
+		return !equal(left, right), nil
+	case "<", "<=", ">", ">=":
+		lf, lok := asFloat(left)
+		rf, rok := asFloat(right)
+		if !lok || !rok {
+			return nil, fmt.Errorf("comparison operator %q requires numeric operands", c.op)
+		}
+		switch c.op {
+		case "<":
+			return lf < rf, nil
+		case "<=":
+			return lf <= rf, nil
+		case ">":
+			return lf > rf, nil
+		default:
+			return lf >= rf, nil
+		}
+	case "in":
+		items, ok := right.([]interface{})
+		if !ok {
+			return false, nil
+		}
+		for _, item := range items {
+			if equal(left, item) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case "startswith":
+		ls, lok := left.(string)
+		rs, rok := right.(string)
+		if !lok || !rok {
+			return false, nil
+		}
+		return strings.HasPrefix(ls, rs), nil
+	case "matches":
+		ls, lok := left.(string)
+		rs, rok := right.(string)
+		if !lok || !rok {
+			return false, nil
+		}
+		re, err := regexp.Compile(rs)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex %q: %w", rs, err)
+		}
+		return re.MatchString(ls), nil
+	default:
+		return nil, fmt.Errorf("unknown comparison operator %q", c.op)
+	}
+}
+
+func truthy(v interface{}) bool {
+	switch t := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return t
+	case float64:
+		return t != 0
+	case string:
+		return t != ""
+	default:
+		return true
+	}
+}
+
+func asFloat(v interface{}) (float64, bool) {
+	switch t := v.(type) {
+	case float64:
+		return t, true
+	case int:
+		return float64(t), true
+	case int64:
+		return float64(t), true
+	default:
+		return 0, false
+	}
+}
+
+func equal(a, b interface{}) bool {
+	if af, aok := asFloat(a); aok {
+		if bf, bok := asFloat(b); bok {
+			return af == bf
+		}
+	}
+	return a == b
+}
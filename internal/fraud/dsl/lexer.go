@@ -0,0 +1,145 @@
+package dsl
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenString
+	tokenIdent
+	tokenRegex
+	tokenOp
+	tokenLParen
+	tokenRParen
+	tokenLBracket
+	tokenRBracket
+	tokenComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// lex tokenizes a rule expression such as:
+//
+//	amount > 1000 and currency == "USD"
+//	velocity.customer_id.1h.count > 5
+//	ip.country in ["RU", "KP"]
+//	device.fingerprint matches /^anon-/
+func lex(input string) ([]token, error) {
+	var tokens []token
+	runes := []rune(input)
+	i := 0
+	for i < len(runes) {
+		r := runes[i]
+		switch {
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			i++
+		case r == '(':
+			tokens = append(tokens, token{tokenLParen, "("})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{tokenRParen, ")"})
+			i++
+		case r == '[':
+			tokens = append(tokens, token{tokenLBracket, "["})
+			i++
+		case r == ']':
+			tokens = append(tokens, token{tokenRBracket, "]"})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{tokenComma, ","})
+			i++
+		case r == '"' || r == '\'':
+			quote := r
+			j := i + 1
+			for j < len(runes) && runes[j] != quote {
+				j++
+			}
+			if j >= len(runes) {
+				return nil, fmt.Errorf("unterminated string literal starting at %d", i)
+			}
+			tokens = append(tokens, token{tokenString, string(runes[i+1 : j])})
+			i = j + 1
+		case r == '/' && startsRegex(tokens):
+			j := i + 1
+			for j < len(runes) && runes[j] != '/' {
+				j++
+			}
+			if j >= len(runes) {
+				return nil, fmt.Errorf("unterminated regex literal starting at %d", i)
+			}
+			tokens = append(tokens, token{tokenRegex, string(runes[i+1 : j])})
+			i = j + 1
+		case isDigit(r):
+			j := i
+			for j < len(runes) && (isDigit(runes[j]) || runes[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, token{tokenNumber, string(runes[i:j])})
+			i = j
+		case isIdentStart(r):
+			j := i
+			for j < len(runes) && isIdentPart(runes[j]) {
+				j++
+			}
+			word := string(runes[i:j])
+			tokens = append(tokens, identOrOp(word))
+			i = j
+		case strings.ContainsRune("<>=!&|+-*/%", r):
+			j := i + 1
+			for j < len(runes) && strings.ContainsRune("=&|", runes[j]) {
+				j++
+			}
+			tokens = append(tokens, token{tokenOp, string(runes[i:j])})
+			i = j
+		default:
+			return nil, fmt.Errorf("unexpected character %q at %d", r, i)
+		}
+	}
+	tokens = append(tokens, token{tokenEOF, ""})
+	return tokens, nil
+}
+
+// startsRegex reports whether a '/' at the current lex position begins a
+// `matches /regex/` literal rather than the division operator, based on the
+// previous token: division can only follow something that already produced
+// a value (an identifier/path, number, string, regex, or closing paren/
+// bracket). Anywhere else — start of input, after an operator, after '(' or
+// ',' — a value is expected next, so '/' must be a regex literal.
+func startsRegex(tokens []token) bool {
+	if len(tokens) == 0 {
+		return true
+	}
+	switch tokens[len(tokens)-1].kind {
+	case tokenIdent, tokenNumber, tokenString, tokenRegex, tokenRParen, tokenRBracket:
+		return false
+	default:
+		return true
+	}
+}
+
+func identOrOp(word string) token {
+	switch strings.ToLower(word) {
+	case "and", "or", "not", "in", "startswith", "matches":
+		return token{tokenOp, strings.ToLower(word)}
+	default:
+		return token{tokenIdent, word}
+	}
+}
+
+func isDigit(r rune) bool { return r >= '0' && r <= '9' }
+
+func isIdentStart(r rune) bool {
+	return r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
+
+func isIdentPart(r rune) bool {
+	return isIdentStart(r) || isDigit(r) || r == '.'
+}
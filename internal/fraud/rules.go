@@ -1,14 +1,38 @@
 package fraud
 
+import (
+	"time"
+
+	"github.com/kodra-pay/fraud-service/internal/repository"
+)
+
 // FraudRule defines the structure for a configurable fraud rule.
 type FraudRule struct {
 	ID          string
 	Description string
 	Threshold   float64   // Threshold for the rule to trigger
 	ScoreImpact float64   // Score added if this rule is triggered
-	Decision    string    // "approve", "flag", "deny" if this rule is triggered
+	Decision    string    // "approve", "challenge", "deny" if this rule is triggered
 	Enabled     bool
 	Predicate   func(transactionData map[string]interface{}) (bool, error) // Function to evaluate the rule
+
+	// VelocityKey, when non-empty, names the transactionData field used to
+	// build the velocity counter key (e.g. "customer_id", "origin",
+	// "device_id"), making this a declarative aggregate rule evaluated
+	// against a VelocityRepository instead of Predicate.
+	VelocityKey string
+	// Window is the trailing window the aggregate is computed over, e.g.
+	// time.Minute, 5*time.Minute, time.Hour, 24*time.Hour.
+	Window time.Duration
+	// Aggregation selects how entries in Window are combined before
+	// comparing against Threshold.
+	Aggregation repository.VelocityAggregation
+
+	// Shadow, when true, lets a rule accumulate score into
+	// FraudDecision.Reasons without affecting FraudDecision.Decision, so a
+	// newly authored rule can be observed in production before it's trusted
+	// to actually block or challenge transactions.
+	Shadow bool
 }
 
 // DefaultRules provides a set of example fraud rules.
@@ -19,7 +43,7 @@ func DefaultRules() []FraudRule {
 			Description: "Flags transactions with amounts exceeding a high threshold.",
 			Threshold:   1000.00,
 			ScoreImpact: 50.0,
-			Decision:    "flag",
+			Decision:    "challenge",
 			Enabled:     true,
 			Predicate: func(transactionData map[string]interface{}) (bool, error) {
 				if amount, ok := transactionData["amount"].(float64); ok {
@@ -33,7 +57,7 @@ func DefaultRules() []FraudRule {
 			Description: "Flags transactions originating from suspicious IP addresses.",
 			Threshold:   1.0, // This rule is binary, so threshold of 1 means it either matches or not
 			ScoreImpact: 70.0,
-			Decision:    "flag",
+			Decision:    "challenge",
 			Enabled:     true,
 			Predicate: func(transactionData map[string]interface{}) (bool, error) {
 				if origin, ok := transactionData["origin"].(string); ok {
@@ -45,15 +69,94 @@ func DefaultRules() []FraudRule {
 		},
 		{
 			ID:          "HIGH_VELOCITY_CUSTOMER",
-			Description: "Flags customers with unusually high transaction velocity.",
+			Description: "Flags customers with unusually high transaction velocity over the last 24 hours.",
 			Threshold:   5.0, // More than 5 transactions in a given lookback period
 			ScoreImpact: 60.0,
-			Decision:    "flag",
+			Decision:    "challenge",
+			Enabled:     true,
+			VelocityKey: "customer_id",
+			Window:      24 * time.Hour,
+			Aggregation: repository.VelocityAggregationCount,
+		},
+		{
+			ID:          "BURST_VELOCITY_CUSTOMER",
+			Description: "Flags customers submitting a burst of transactions within a single minute.",
+			Threshold:   3.0,
+			ScoreImpact: 40.0,
+			Decision:    "challenge",
+			Enabled:     true,
+			VelocityKey: "customer_id",
+			Window:      time.Minute,
+			Aggregation: repository.VelocityAggregationCount,
+		},
+		{
+			ID:          "MULTI_MERCHANT_DEVICE",
+			Description: "Flags a device used across an unusual number of distinct merchants within 5 minutes.",
+			Threshold:   3.0,
+			ScoreImpact: 45.0,
+			Decision:    "challenge",
+			Enabled:     true,
+			VelocityKey: "device_id",
+			Window:      5 * time.Minute,
+			Aggregation: repository.VelocityAggregationDistinctMerchant,
+		},
+		{
+			ID:          "HIGH_SPEND_VELOCITY_CARD_BIN",
+			Description: "Flags a card BIN accumulating unusually high spend within an hour.",
+			Threshold:   5000.0,
+			ScoreImpact: 55.0,
+			Decision:    "challenge",
+			Enabled:     true,
+			VelocityKey: "card_bin",
+			Window:      time.Hour,
+			Aggregation: repository.VelocityAggregationSumAmount,
+		},
+		{
+			// Handled as a special case in RuleBasedFraudDetector.CheckTransaction,
+			// since it needs both IP enrichment and the customer's transaction
+			// history. Threshold is the minimum distance in kilometers and Window
+			// is the time span two transactions from that far apart must fall
+			// within to be "impossible" for the same customer to have traveled.
+			ID:          "GEO_VELOCITY_IMPOSSIBLE_TRAVEL",
+			Description: "Flags a customer transacting from two countries farther apart than physically possible in the elapsed time.",
+			Threshold:   800.0, // km
+			ScoreImpact: 80.0,
+			Decision:    "challenge",
+			Enabled:     true,
+			Window:      time.Hour,
+		},
+		{
+			ID:          "MISMATCHED_BILLING_IP_COUNTRY",
+			Description: "Flags a transaction whose billing country doesn't match the IP's resolved country.",
+			ScoreImpact: 35.0,
+			Decision:    "challenge",
 			Enabled:     true,
 			Predicate: func(transactionData map[string]interface{}) (bool, error) {
-				// This predicate would ideally use the FraudDataRepository to check velocity
-				// For now, it's a placeholder that would be integrated with the detector's repo
-				return false, nil
+				billingCountry, ok := transactionData["billing_country"].(string)
+				if !ok || billingCountry == "" {
+					return false, nil
+				}
+				ip, ok := transactionData["ip"].(map[string]interface{})
+				if !ok {
+					return false, nil
+				}
+				ipCountry, _ := ip["country"].(string)
+				return ipCountry != "" && ipCountry != billingCountry, nil
+			},
+		},
+		{
+			ID:          "DATACENTER_ASN_ORIGIN",
+			Description: "Flags a transaction originating from a known hosting/VPN ASN instead of a residential or mobile network.",
+			ScoreImpact: 45.0,
+			Decision:    "challenge",
+			Enabled:     true,
+			Predicate: func(transactionData map[string]interface{}) (bool, error) {
+				ip, ok := transactionData["ip"].(map[string]interface{})
+				if !ok {
+					return false, nil
+				}
+				isHosting, _ := ip["is_hosting"].(bool)
+				return isHosting, nil
 			},
 		},
 		// Add more rules as per industry practices
@@ -63,6 +166,13 @@ func DefaultRules() []FraudRule {
 // FraudDecision represents the outcome of a fraud evaluation.
 type FraudDecision struct {
 	OverallScore float64
-	Decision     string // "approve", "flag", "deny"
+	Decision     string // "approve", "challenge", "deny"
 	Reasons      []string
+
+	// AdvisoryOnly is set when the caller's daily rate-limit quota was
+	// exceeded and its merchant is shadow-downgraded (see
+	// ratelimit.Merchant.ShadowOnQuotaExceeded): Decision is still computed
+	// normally, but a caller should treat it as informational rather than
+	// enforce it.
+	AdvisoryOnly bool
 }
@@ -0,0 +1,199 @@
+package fraud
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RuleEngineScorerName is the ScorerResult.Source FraudService uses for the
+// rule engine's own contribution to an ensemble (see FraudService.
+// CheckTransaction): the rule engine already runs once as the base
+// detector pass, so its score is folded into the ensemble under this name
+// instead of re-running it as a registered Scorer.
+const RuleEngineScorerName = "rule_engine"
+
+// ScorerResult captures a single scorer's contribution to a fraud decision.
+type ScorerResult struct {
+	Source  string
+	Score   float64
+	Reasons []string
+	Err     error
+}
+
+// Scorer is implemented by anything that can produce a risk score for a
+// transaction. Implementations should be safe for concurrent use, since the
+// registry invokes every enabled scorer concurrently.
+type Scorer interface {
+	// Name identifies the scorer in FraudDecision.Reasons and admin output.
+	Name() string
+	// Score returns a risk score in [0, 100], the reasons behind it, and an
+	// error if the scorer could not evaluate the transaction.
+	Score(ctx context.Context, transactionData map[string]interface{}) (float64, []string, error)
+}
+
+// Aggregation selects how individual scorer outputs are combined into the
+// FraudDecision.OverallScore.
+type Aggregation string
+
+const (
+	// AggregationWeightedSum combines scorers using their registered weights.
+	AggregationWeightedSum Aggregation = "weighted_sum"
+	// AggregationMax takes the highest individual scorer score.
+	AggregationMax Aggregation = "max"
+	// AggregationStacked feeds scorer outputs into a meta-model.
+	AggregationStacked Aggregation = "stacked"
+)
+
+// registeredScorer pairs a Scorer with its weight and enabled state.
+type registeredScorer struct {
+	scorer  Scorer
+	weight  float64
+	enabled bool
+}
+
+// ScorerRegistry holds the set of scorers an ensemble detector dispatches to.
+type ScorerRegistry struct {
+	mu      sync.RWMutex
+	scorers map[string]*registeredScorer
+}
+
+// NewScorerRegistry creates an empty registry.
+func NewScorerRegistry() *ScorerRegistry {
+	return &ScorerRegistry{scorers: make(map[string]*registeredScorer)}
+}
+
+// Register adds or replaces a scorer under its own name with the given
+// weight. New scorers are enabled by default.
+func (r *ScorerRegistry) Register(scorer Scorer, weight float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.scorers[scorer.Name()] = &registeredScorer{scorer: scorer, weight: weight, enabled: true}
+}
+
+// SetEnabled toggles whether a registered scorer participates in scoring.
+func (r *ScorerRegistry) SetEnabled(name string, enabled bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rs, ok := r.scorers[name]
+	if !ok {
+		return fmt.Errorf("scorer %q is not registered", name)
+	}
+	rs.enabled = enabled
+	return nil
+}
+
+// Enabled returns the scorers currently participating in scoring.
+func (r *ScorerRegistry) Enabled() []Scorer {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var out []Scorer
+	for _, rs := range r.scorers {
+		if rs.enabled {
+			out = append(out, rs.scorer)
+		}
+	}
+	return out
+}
+
+func (r *ScorerRegistry) weightFor(name string) float64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if rs, ok := r.scorers[name]; ok {
+		return rs.weight
+	}
+	return 0
+}
+
+// Dispatch runs every enabled scorer concurrently, bounding each by
+// perScorerTimeout, and returns one ScorerResult per scorer. A scorer that
+// times out or errors still produces a result with Err set so callers can
+// decide whether to include it in the audit trail.
+func (r *ScorerRegistry) Dispatch(ctx context.Context, transactionData map[string]interface{}, perScorerTimeout time.Duration) []ScorerResult {
+	scorers := r.Enabled()
+	results := make([]ScorerResult, len(scorers))
+
+	var wg sync.WaitGroup
+	wg.Add(len(scorers))
+	for i, scorer := range scorers {
+		go func(i int, scorer Scorer) {
+			defer wg.Done()
+			scorerCtx, cancel := context.WithTimeout(ctx, perScorerTimeout)
+			defer cancel()
+
+			done := make(chan ScorerResult, 1)
+			go func() {
+				score, reasons, err := scorer.Score(scorerCtx, transactionData)
+				done <- ScorerResult{Source: scorer.Name(), Score: score, Reasons: reasons, Err: err}
+			}()
+
+			select {
+			case res := <-done:
+				results[i] = res
+			case <-scorerCtx.Done():
+				results[i] = ScorerResult{Source: scorer.Name(), Err: fmt.Errorf("scorer %s timed out after %s", scorer.Name(), perScorerTimeout)}
+			}
+		}(i, scorer)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// Combine aggregates scorer results into a single score using mode, weighting
+// contributions via the registry's registered weights where applicable.
+// Errored results are excluded from the combined score but still useful to
+// the caller for the audit trail.
+func (r *ScorerRegistry) Combine(results []ScorerResult, mode Aggregation) float64 {
+	switch mode {
+	case AggregationMax:
+		var max float64
+		for _, res := range results {
+			if res.Err != nil {
+				continue
+			}
+			if res.Score > max {
+				max = res.Score
+			}
+		}
+		return max
+	case AggregationStacked:
+		// A real stacked meta-model would feed scorer outputs as features
+		// into a trained combiner; until one is wired in, fall back to an
+		// averaged blend of the available scores.
+		var sum float64
+		var n int
+		for _, res := range results {
+			if res.Err != nil {
+				continue
+			}
+			sum += res.Score
+			n++
+		}
+		if n == 0 {
+			return 0
+		}
+		return sum / float64(n)
+	case AggregationWeightedSum:
+		fallthrough
+	default:
+		// A true weighted sum, not an average: each scorer's weight says how
+		// much it contributes to the combined score, not what share of it it
+		// owns, so one high-confidence scorer (e.g. the rule engine) can't
+		// be diluted down below its own threshold-crossing score just
+		// because other scorers saw nothing suspicious.
+		var sum float64
+		for _, res := range results {
+			if res.Err != nil {
+				continue
+			}
+			w := r.weightFor(res.Source)
+			if w == 0 {
+				w = 1
+			}
+			sum += res.Score * w
+		}
+		return sum
+	}
+}
@@ -0,0 +1,69 @@
+package fraud
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// VelocityScorer flags customers transacting unusually often within a short
+// window. It tracks counts in memory keyed by customer_id; the counter
+// storage is intentionally isolated behind this struct so it can be swapped
+// for a shared backend without changing the Scorer contract.
+type VelocityScorer struct {
+	window    time.Duration
+	threshold int
+
+	mu         sync.Mutex
+	timestamps map[string][]time.Time
+}
+
+// NewVelocityScorer creates a scorer that flags a customer once they exceed
+// threshold transactions within window.
+func NewVelocityScorer(window time.Duration, threshold int) *VelocityScorer {
+	return &VelocityScorer{
+		window:     window,
+		threshold:  threshold,
+		timestamps: make(map[string][]time.Time),
+	}
+}
+
+// Name identifies this scorer's contributions in FraudDecision.Reasons.
+func (s *VelocityScorer) Name() string { return "velocity" }
+
+// Score records the current transaction and scores based on how many
+// transactions the same customer has made within the configured window.
+func (s *VelocityScorer) Score(_ context.Context, transactionData map[string]interface{}) (float64, []string, error) {
+	customerID, ok := transactionData["customer_id"].(string)
+	if !ok || customerID == "" {
+		return 0, nil, nil
+	}
+
+	now := time.Now()
+	cutoff := now.Add(-s.window)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	recent := s.timestamps[customerID][:0]
+	for _, ts := range s.timestamps[customerID] {
+		if ts.After(cutoff) {
+			recent = append(recent, ts)
+		}
+	}
+	recent = append(recent, now)
+	s.timestamps[customerID] = recent
+
+	count := len(recent)
+	if count <= s.threshold {
+		return 0, nil, nil
+	}
+
+	over := float64(count - s.threshold)
+	score := over / float64(s.threshold) * 100
+	if score > 100 {
+		score = 100
+	}
+	return score, []string{fmt.Sprintf("customer %s made %d transactions in %s (threshold %d)", customerID, count, s.window, s.threshold)}, nil
+}
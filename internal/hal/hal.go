@@ -0,0 +1,41 @@
+// Package hal implements a small HAL (Hypertext Application Language)
+// response helper, so fraud API responses can point a client at related
+// resources (a challenge, a refund, a merchant) instead of requiring it to
+// hard-code URLs.
+package hal
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Link is one entry of a HAL "_links" section.
+type Link struct {
+	Href string `json:"href"`
+}
+
+// SendHAL writes data as a HAL+JSON document: data's own fields merged with
+// a "_links" section built from links and, if non-empty, an "_embedded"
+// section built from embedded. data must marshal to a JSON object.
+func SendHAL(c *fiber.Ctx, status int, data interface{}, links map[string]Link, embedded map[string]interface{}) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("hal: failed to marshal data: %w", err)
+	}
+
+	doc := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return fmt.Errorf("hal: data must marshal to a JSON object: %w", err)
+	}
+	if len(links) > 0 {
+		doc["_links"] = links
+	}
+	if len(embedded) > 0 {
+		doc["_embedded"] = embedded
+	}
+
+	c.Set(fiber.HeaderContentType, "application/hal+json")
+	return c.Status(status).JSON(doc)
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kodra-pay/fraud-service/internal/cases"
+	"github.com/kodra-pay/fraud-service/internal/middleware"
+)
+
+// CaseAPIHandler exposes the analyst case management workflow over HTTP.
+type CaseAPIHandler struct {
+	manager *cases.CaseManager
+	roles   []middleware.APIKeyIdentity
+}
+
+// NewCaseAPIHandler creates a new instance of CaseAPIHandler. roles
+// authenticates the /cases API; a nil or empty roles leaves it unprotected,
+// which should only happen in local development.
+func NewCaseAPIHandler(manager *cases.CaseManager, roles []middleware.APIKeyIdentity) *CaseAPIHandler {
+	return &CaseAPIHandler{manager: manager, roles: roles}
+}
+
+// CreateCase handles manually opening a case (the common path is the
+// auto-open hook in RuleBasedFraudDetector.CheckTransaction; this covers an
+// analyst opening one by hand, e.g. from an out-of-band report).
+func (h *CaseAPIHandler) CreateCase(c *fiber.Ctx) error {
+	var req struct {
+		TransactionRef  string                 `json:"transaction_ref"`
+		Score           float64                `json:"score"`
+		Reasons         []string               `json:"reasons"`
+		TransactionData map[string]interface{} `json:"transaction_data"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if req.TransactionRef == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "transaction_ref is required")
+	}
+
+	newCase, err := h.manager.OpenCase(c.Context(), req.TransactionRef, req.TransactionData, req.Score, req.Reasons)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+	return c.Status(fiber.StatusCreated).JSON(newCase)
+}
+
+// ListCases handles GET /cases?status=open, returning every case if status
+// is omitted.
+func (h *CaseAPIHandler) ListCases(c *fiber.Ctx) error {
+	status := cases.Status(c.Query("status"))
+	found, err := h.manager.List(c.Context(), status)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(fiber.Map{"cases": found})
+}
+
+// PatchCase handles the analyst applying a status transition and/or
+// reassigning a case. A confirmed_fraud or cleared status also feeds the
+// verdict back into the feedback and blacklist repositories.
+func (h *CaseAPIHandler) PatchCase(c *fiber.Ctx) error {
+	id := c.Params("id")
+	var req struct {
+		Status   cases.Status `json:"status"`
+		Assignee *string      `json:"assignee"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	actor := c.Get("X-API-Key")
+	updated, err := h.manager.UpdateStatus(c.Context(), id, actor, req.Status, req.Assignee)
+	if err != nil {
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	}
+	return c.JSON(updated)
+}
+
+// AddComment handles an analyst leaving a note on a case.
+func (h *CaseAPIHandler) AddComment(c *fiber.Ctx) error {
+	id := c.Params("id")
+	var req struct {
+		Author string `json:"author"`
+		Text   string `json:"text"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if req.Text == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "text is required")
+	}
+	if req.Author == "" {
+		req.Author = c.Get("X-API-Key")
+	}
+
+	updated, err := h.manager.AddComment(c.Context(), id, req.Author, req.Text)
+	if err != nil {
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	}
+	return c.JSON(updated)
+}
+
+// Register adds the case management routes to the Fiber app. Reading and
+// filing cases only requires an authenticated caller; transitioning a
+// case's status is restricted to the analyst and admin roles.
+func (h *CaseAPIHandler) Register(app *fiber.App) {
+	caseGroup := app.Group("/cases")
+	if len(h.roles) > 0 {
+		caseGroup.Use(middleware.RoleAuth(h.roles))
+	}
+
+	caseGroup.Post("/", h.CreateCase)
+	caseGroup.Get("/", h.ListCases)
+	caseGroup.Post("/:id/comments", h.AddComment)
+
+	if len(h.roles) > 0 {
+		caseGroup.Patch("/:id", middleware.RequireRole("analyst", "admin"), h.PatchCase)
+	} else {
+		caseGroup.Patch("/:id", h.PatchCase)
+	}
+}
@@ -0,0 +1,136 @@
+// Package dto holds the typed request bodies bound and validated by
+// middleware.BindAndValidate for the fraud handlers, replacing the ad-hoc
+// map[string]interface{} casts that used to live in FraudAPIHandler.
+package dto
+
+import "encoding/json"
+
+// CheckTransactionRequest is the validated body for POST
+// /fraud/check-transaction and /fraud/check-transaction/async.
+//
+// The fraud engine (rule Predicates, the DSL compiled from hot-reloadable
+// rule specs, and model-backed scorers) reads arbitrary tx.* fields beyond
+// the ones enumerated here, so unknown JSON fields are captured in extra
+// and merged back in by ToTransactionData rather than being dropped.
+type CheckTransactionRequest struct {
+	CustomerID        string  `json:"customer_id" validate:"required"`
+	Amount            float64 `json:"amount" validate:"required,gt=0"`
+	Currency          string  `json:"currency" validate:"required,iso4217"`
+	Reference         string  `json:"reference,omitempty"`
+	MerchantID        string  `json:"merchant_id,omitempty"`
+	CardBin           string  `json:"card_bin,omitempty"`
+	Origin            string  `json:"origin,omitempty" validate:"omitempty,ip"`
+	DeviceID          string  `json:"device_id,omitempty"`
+	DeviceFingerprint string  `json:"device_fingerprint,omitempty"`
+	BillingCountry    string  `json:"billing_country,omitempty" validate:"omitempty,len=2"`
+
+	extra map[string]interface{}
+}
+
+// knownCheckTransactionFields mirrors the json tags above, used by
+// UnmarshalJSON to work out which incoming keys are "extra".
+var knownCheckTransactionFields = map[string]bool{
+	"customer_id": true, "amount": true, "currency": true, "reference": true,
+	"merchant_id": true, "card_bin": true, "origin": true, "device_id": true,
+	"device_fingerprint": true, "billing_country": true,
+}
+
+// UnmarshalJSON decodes the known, validated fields via the default struct
+// mapping and stashes any remaining keys in extra so ToTransactionData can
+// round-trip fields the rule engine references but this DTO doesn't know
+// about by name.
+func (r *CheckTransactionRequest) UnmarshalJSON(data []byte) error {
+	type alias CheckTransactionRequest
+	if err := json.Unmarshal(data, (*alias)(r)); err != nil {
+		return err
+	}
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	for key := range knownCheckTransactionFields {
+		delete(raw, key)
+	}
+	r.extra = raw
+	return nil
+}
+
+// ToTransactionData converts the validated request into the
+// map[string]interface{} shape services.FraudService and the underlying
+// rule/scorer engine expect.
+func (r CheckTransactionRequest) ToTransactionData() map[string]interface{} {
+	data := map[string]interface{}{
+		"customer_id": r.CustomerID,
+		"amount":      r.Amount,
+		"currency":    r.Currency,
+	}
+	if r.Reference != "" {
+		data["reference"] = r.Reference
+	}
+	if r.MerchantID != "" {
+		data["merchant_id"] = r.MerchantID
+	}
+	if r.CardBin != "" {
+		data["card_bin"] = r.CardBin
+	}
+	if r.Origin != "" {
+		data["origin"] = r.Origin
+	}
+	if r.DeviceID != "" {
+		data["device_id"] = r.DeviceID
+	}
+	if r.DeviceFingerprint != "" {
+		data["device_fingerprint"] = r.DeviceFingerprint
+	}
+	if r.BillingCountry != "" {
+		data["billing_country"] = r.BillingCountry
+	}
+	for key, value := range r.extra {
+		data[key] = value
+	}
+	return data
+}
+
+// ValidatePaymentChannelRequest is the validated body for POST
+// /fraud/validate-payment-channel.
+type ValidatePaymentChannelRequest struct {
+	ChannelType   string `json:"channel_type" validate:"required"`
+	TransactionID string `json:"transaction_id" validate:"required"`
+}
+
+// ToChannelData converts the validated request into the
+// map[string]interface{} shape RuleBasedFraudDetector.ValidatePaymentChannel
+// expects.
+func (r ValidatePaymentChannelRequest) ToChannelData() map[string]interface{} {
+	return map[string]interface{}{
+		"channel_type":   r.ChannelType,
+		"transaction_id": r.TransactionID,
+	}
+}
+
+// TrackPaymentLinkRequest is the validated body for POST
+// /fraud/track-payment-link.
+type TrackPaymentLinkRequest struct {
+	URL string `json:"url" validate:"required,url"`
+}
+
+// ToLinkData converts the validated request into the
+// map[string]interface{} shape RuleBasedFraudDetector.TrackPaymentLink
+// expects.
+func (r TrackPaymentLinkRequest) ToLinkData() map[string]interface{} {
+	return map[string]interface{}{"url": r.URL}
+}
+
+// StartChallengeRequest is the validated body for POST
+// /fraud/challenges/:id/start.
+type StartChallengeRequest struct {
+	FactorID string `json:"factor_id" validate:"required"`
+}
+
+// VerifyChallengeRequest is the validated body for POST
+// /fraud/challenges/:id/verify.
+type VerifyChallengeRequest struct {
+	FactorID string `json:"factor_id" validate:"required"`
+	Secret   string `json:"secret" validate:"required"`
+}
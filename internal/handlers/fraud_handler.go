@@ -1,17 +1,44 @@
 package handlers
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"log"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/limiter"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"github.com/kodra-pay/fraud-service/internal/challenges"
+	"github.com/kodra-pay/fraud-service/internal/events"
+	"github.com/kodra-pay/fraud-service/internal/fraud"
+	"github.com/kodra-pay/fraud-service/internal/hal"
+	"github.com/kodra-pay/fraud-service/internal/handlers/dto"
+	"github.com/kodra-pay/fraud-service/internal/middleware"
 	"github.com/kodra-pay/fraud-service/internal/services"
 )
 
+// Event types published by FraudAPIHandler after every decision; see
+// decisionEventPayload and emitDecisionEvent.
+const (
+	fraudDecisionEventType      = "fraud.decision"
+	paymentLinkFlaggedEventType = "payment_link.flagged"
+	channelValidationEventType  = "channel.validation"
+	challengeStartedEventType   = "challenge.started"
+	challengeCompletedEventType = "challenge.completed"
+)
+
 // FraudAPIHandler handles HTTP requests related to fraud detection.
 type FraudAPIHandler struct {
-	svc *services.FraudService
+	svc        *services.FraudService
+	ruleLoader *fraud.RuleLoader
+
+	decisionEmitter  *events.AsyncEmitter
+	dispatcher       *events.Dispatcher
+	challengeManager *challenges.Manager
+	rateLimiter      fiber.Handler
 }
 
 // NewFraudAPIHandler creates a new instance of FraudAPIHandler.
@@ -19,53 +46,280 @@ func NewFraudAPIHandler(svc *services.FraudService) *FraudAPIHandler {
 	return &FraudAPIHandler{svc: svc}
 }
 
-// CheckTransaction handles the request to check a transaction for fraud.
-func (h *FraudAPIHandler) CheckTransaction(c *fiber.Ctx) error {
-	var transactionData map[string]interface{}
-	if err := c.BodyParser(&transactionData); err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+// WithRuleLoader enables the /rules/reload admin endpoint, backed by loader.
+func (h *FraudAPIHandler) WithRuleLoader(loader *fraud.RuleLoader) *FraudAPIHandler {
+	h.ruleLoader = loader
+	return h
+}
+
+// WithDecisionEmitter enables fire-and-forget publishing of a decision event
+// after every CheckTransaction, TrackPaymentLink, and ValidatePaymentChannel
+// call. Publishing runs on emitter's background workers so a slow or
+// unreachable consumer never adds latency to the API response.
+func (h *FraudAPIHandler) WithDecisionEmitter(emitter *events.AsyncEmitter) *FraudAPIHandler {
+	h.decisionEmitter = emitter
+	return h
+}
+
+// WithDispatcher enables the /events/replay admin endpoint, backed by
+// dispatcher's outbox.
+func (h *FraudAPIHandler) WithDispatcher(dispatcher *events.Dispatcher) *FraudAPIHandler {
+	h.dispatcher = dispatcher
+	return h
+}
+
+// WithChallengeManager enables step-up verification: CheckTransaction
+// responds 202 with a Challenge descriptor instead of the bare decision
+// whenever FraudDecision.Decision is "challenge", and the
+// /fraud/challenges/:id/start and /fraud/challenges/:id/verify routes become
+// available.
+func (h *FraudAPIHandler) WithChallengeManager(manager *challenges.Manager) *FraudAPIHandler {
+	h.challengeManager = manager
+	return h
+}
+
+// WithRateLimiter replaces the fixed 5-req/sec limiter.New fallback
+// Register otherwise uses with a per-merchant rate limiter (see
+// middleware.RateLimit), keyed on the caller's API key rather than a single
+// limit shared by everyone.
+func (h *FraudAPIHandler) WithRateLimiter(rateLimiter fiber.Handler) *FraudAPIHandler {
+	h.rateLimiter = rateLimiter
+	return h
+}
+
+// DecisionEmitter returns the AsyncEmitter configured by WithDecisionEmitter
+// (nil if none), so routes.go can reuse the same fire-and-forget publisher
+// for rate-limit's quota.exceeded event instead of standing up a second one.
+func (h *FraudAPIHandler) DecisionEmitter() *events.AsyncEmitter {
+	return h.decisionEmitter
+}
+
+// decisionEventPayload is the Event.Data published after every fraud
+// decision, carrying enough context for a downstream consumer (ledger,
+// notification, ML retraining) to act without calling back into this
+// service.
+type decisionEventPayload struct {
+	RequestID   string   `json:"request_id"`
+	APIKeyID    string   `json:"api_key_id"`
+	PayloadHash string   `json:"payload_hash"`
+	Decision    string   `json:"decision"`
+	Score       float64  `json:"score,omitempty"`
+	Reasons     []string `json:"reasons,omitempty"`
+}
+
+// emitDecisionEvent builds and queues a decisionEventPayload for background
+// publishing. It is a no-op when no decision emitter is configured.
+func (h *FraudAPIHandler) emitDecisionEvent(c *fiber.Ctx, eventType, decision string, score float64, reasons []string) {
+	if h.decisionEmitter == nil {
+		return
 	}
 
-	// Manual input validation
-	customerID, ok := transactionData["customer_id"].(string)
-	if !ok || customerID == "" {
-		return fiber.NewError(fiber.StatusBadRequest, "customer_id is required and must be a string")
+	hash := sha256.Sum256(c.Body())
+	requestID, _ := c.Locals(requestid.ConfigDefault.ContextKey).(string)
+	payload := decisionEventPayload{
+		RequestID:   requestID,
+		APIKeyID:    c.Get("X-API-Key"),
+		PayloadHash: hex.EncodeToString(hash[:]),
+		Decision:    decision,
+		Score:       score,
+		Reasons:     reasons,
 	}
-	amount, ok := transactionData["amount"].(float64)
-	if !ok || amount <= 0 {
-		return fiber.NewError(fiber.StatusBadRequest, "amount is required and must be a positive number")
+
+	event, err := events.NewEvent(eventType, payload)
+	if err != nil {
+		log.Printf("fraud: failed to encode %s event: %v", eventType, err)
+		return
 	}
-	currency, ok := transactionData["currency"].(string)
-	if !ok || currency == "" {
-		return fiber.NewError(fiber.StatusBadRequest, "currency is required and must be a string")
+	h.decisionEmitter.Emit(eventType, event)
+}
+
+// challengeEventPayload is the Event.Data published for challenge.started
+// and challenge.completed, so a downstream consumer can track step-up
+// verification outcomes without polling the challenge.
+type challengeEventPayload struct {
+	ChallengeID string `json:"challenge_id"`
+	FactorID    string `json:"factor_id"`
+	Outcome     string `json:"outcome,omitempty"`
+}
+
+// emitChallengeEvent builds and queues a challengeEventPayload for
+// background publishing. It is a no-op when no decision emitter is
+// configured.
+func (h *FraudAPIHandler) emitChallengeEvent(eventType, challengeID, factorID, outcome string) {
+	if h.decisionEmitter == nil {
+		return
 	}
 
-	decision, err := h.svc.CheckTransaction(c.Context(), transactionData)
+	event, err := events.NewEvent(eventType, challengeEventPayload{
+		ChallengeID: challengeID,
+		FactorID:    factorID,
+		Outcome:     outcome,
+	})
+	if err != nil {
+		log.Printf("fraud: failed to encode %s event: %v", eventType, err)
+		return
+	}
+	h.decisionEmitter.Emit(eventType, event)
+}
+
+// CheckTransaction handles the request to check a transaction for fraud.
+// The request body is bound and validated by middleware.BindAndValidate
+// (see Register); by the time this handler runs, req is already known to
+// satisfy its validate tags. A "challenge" decision short-circuits the
+// usual JSON response with a 202 and a Challenge descriptor instead (see
+// respondWithChallenge).
+func (h *FraudAPIHandler) CheckTransaction(c *fiber.Ctx) error {
+	req := middleware.ValidatedBody(c).(*dto.CheckTransactionRequest)
+
+	decision, err := h.svc.CheckTransaction(c.Context(), req.ToTransactionData())
 	if err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
+	if shadow, _ := c.Locals(middleware.ShadowModeLocalsKey).(bool); shadow {
+		decision.AdvisoryOnly = true
+	}
+
+	h.emitDecisionEvent(c, fraudDecisionEventType, decision.Decision, decision.OverallScore, decision.Reasons)
+
+	if decision.Decision == "challenge" && h.challengeManager != nil {
+		return h.respondWithChallenge(c, req.Reference, decision)
+	}
 
 	return c.JSON(decision)
 }
 
-// TrackPaymentLink handles the request to track a payment link for suspicious activity.
-func (h *FraudAPIHandler) TrackPaymentLink(c *fiber.Ctx) error {
-	var linkData struct {
-		URL string `json:"url"`
+// respondWithChallenge persists a pending step-up verification Challenge for
+// transactionRef, capturing the caller's IP and User-Agent so a later
+// start/verify call from a different device is rejected, and responds 202
+// Accepted with the challenge descriptor and its allowed factors.
+func (h *FraudAPIHandler) respondWithChallenge(c *fiber.Ctx, transactionRef string, decision fraud.FraudDecision) error {
+	challenge, err := h.challengeManager.New(c.Context(), transactionRef, nil, 0, c.IP(), c.Get("User-Agent"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
-	if err := c.BodyParser(&linkData); err != nil {
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"decision":        decision,
+		"challenge_id":    challenge.ID,
+		"allowed_factors": challenge.AllowedFactors,
+		"expires_at":      challenge.ExpiresAt,
+	})
+}
+
+// StartChallenge handles the request to dispatch one of a challenge's
+// allowed factors (e.g. send the SMS/email OTP), via the configured
+// FactorDispatcher.
+func (h *FraudAPIHandler) StartChallenge(c *fiber.Ctx) error {
+	if h.challengeManager == nil {
+		return fiber.NewError(fiber.StatusNotFound, "step-up verification is not configured")
+	}
+	req := middleware.ValidatedBody(c).(*dto.StartChallengeRequest)
+	challengeID := c.Params("id")
+
+	if err := h.challengeManager.StartFactor(c.Context(), challengeID, req.FactorID); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	h.emitChallengeEvent(challengeStartedEventType, challengeID, req.FactorID, "")
+
+	return c.JSON(fiber.Map{"status": "dispatched"})
+}
+
+// VerifyChallenge handles the request to verify the secret returned by the
+// factor started via StartChallenge, confirming it was received from the
+// same IP/User-Agent the challenge was created for. A successful verify
+// approves the transaction; a wrong secret counts as a failed attempt and
+// locks the challenge after challenges.MaxAttempts.
+func (h *FraudAPIHandler) VerifyChallenge(c *fiber.Ctx) error {
+	if h.challengeManager == nil {
+		return fiber.NewError(fiber.StatusNotFound, "step-up verification is not configured")
+	}
+	req := middleware.ValidatedBody(c).(*dto.VerifyChallengeRequest)
+	challengeID := c.Params("id")
+
+	approved, err := h.challengeManager.Verify(c.Context(), challengeID, req.FactorID, req.Secret, c.IP(), c.Get("User-Agent"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	if current, err := h.challengeManager.Get(c.Context(), challengeID); err == nil && (approved || current.Status == challenges.StatusLocked) {
+		outcome := "approved"
+		if !approved {
+			outcome = "locked"
+		}
+		h.emitChallengeEvent(challengeCompletedEventType, challengeID, req.FactorID, outcome)
+	}
+
+	if !approved {
+		return fiber.NewError(fiber.StatusUnauthorized, "incorrect verification code")
+	}
+	return c.JSON(fiber.Map{"status": "approved"})
+}
+
+// CheckTransactionAsync handles the request to submit a transaction for
+// asynchronous fraud evaluation, returning immediately instead of waiting
+// for a decision. Requires async mode (EVENTS_DRIVER) to be configured.
+func (h *FraudAPIHandler) CheckTransactionAsync(c *fiber.Ctx) error {
+	req := middleware.ValidatedBody(c).(*dto.CheckTransactionRequest)
+
+	if err := h.svc.CheckTransactionAsync(c.Context(), req.ToTransactionData()); err != nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, err.Error())
+	}
+
+	return c.SendStatus(fiber.StatusAccepted)
+}
+
+// IssuePaymentLink handles the request to issue a signed, tamper-evident
+// payment link for a transaction.
+func (h *FraudAPIHandler) IssuePaymentLink(c *fiber.Ctx) error {
+	var req struct {
+		Reference  string `json:"reference"`
+		MerchantID int    `json:"merchant_id"`
+		Amount     int64  `json:"amount"`
+		Currency   string `json:"currency"`
+		Mode       string `json:"mode"`
+		TTLSeconds int64  `json:"ttl_seconds"`
+	}
+	if err := c.BodyParser(&req); err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
 	}
+	if req.Reference == "" || req.MerchantID == 0 || req.Amount <= 0 || req.Currency == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "reference, merchant_id, amount, and currency are required")
+	}
+	if req.TTLSeconds <= 0 {
+		req.TTLSeconds = int64(time.Hour.Seconds())
+	}
 
-	if linkData.URL == "" {
-		return fiber.NewError(fiber.StatusBadRequest, "url is required")
+	query, err := h.svc.IssuePaymentLink(services.PaymentLinkParams{
+		Reference:  req.Reference,
+		MerchantID: req.MerchantID,
+		Amount:     req.Amount,
+		Currency:   req.Currency,
+		Mode:       req.Mode,
+		Exp:        time.Now().Add(time.Duration(req.TTLSeconds) * time.Second),
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
 
-	isSuspicious, reason, err := h.svc.TrackPaymentLink(c.Context(), map[string]interface{}{"url": linkData.URL})
+	return c.JSON(fiber.Map{"query": query})
+}
+
+// TrackPaymentLink handles the request to track a payment link for suspicious activity.
+func (h *FraudAPIHandler) TrackPaymentLink(c *fiber.Ctx) error {
+	req := middleware.ValidatedBody(c).(*dto.TrackPaymentLinkRequest)
+
+	isSuspicious, reason, err := h.svc.TrackPaymentLink(c.Context(), req.ToLinkData())
 	if err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
 
+	decision := "clear"
+	if isSuspicious {
+		decision = "flag"
+	}
+	h.emitDecisionEvent(c, paymentLinkFlaggedEventType, decision, 0, []string{reason})
+
 	return c.JSON(fiber.Map{
 		"is_suspicious": isSuspicious,
 		"reason":        reason,
@@ -74,63 +328,189 @@ func (h *FraudAPIHandler) TrackPaymentLink(c *fiber.Ctx) error {
 
 // ValidatePaymentChannel handles the request to validate a transaction via a payment channel.
 func (h *FraudAPIHandler) ValidatePaymentChannel(c *fiber.Ctx) error {
-	var channelData map[string]interface{}
-	if err := c.BodyParser(&channelData); err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
-	}
+	req := middleware.ValidatedBody(c).(*dto.ValidatePaymentChannelRequest)
 
-	// Manual input validation
-	channelType, ok := channelData["channel_type"].(string)
-	if !ok || channelType == "" {
-		return fiber.NewError(fiber.StatusBadRequest, "channel_type is required and must be a string")
-	}
-	transactionID, ok := channelData["transaction_id"].(string)
-	if !ok || transactionID == "" {
-		return fiber.NewError(fiber.StatusBadRequest, "transaction_id is required and must be a string")
-	}
-
-	isValid, reason, err := h.svc.ValidatePaymentChannel(c.Context(), channelData)
+	isValid, reason, err := h.svc.ValidatePaymentChannel(c.Context(), req.ToChannelData())
 	if err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
 
+	decision := "invalid"
+	if isValid {
+		decision = "valid"
+	}
+	h.emitDecisionEvent(c, channelValidationEventType, decision, 0, []string{reason})
+
 	return c.JSON(fiber.Map{
 		"is_valid": isValid,
 		"reason":   reason,
 	})
 }
 
-// GetTransactionDetails handles the request to get transaction details by reference.
+// GetTransactionDetails handles the request to get transaction details by
+// reference, returned as a HAL document so a client can navigate the fraud
+// graph (its challenge, the merchant, a refund, other transactions by the
+// same customer) instead of hard-coding those URLs. middleware.
+// CheckTransactionExists has already resolved :reference and stashed it in
+// c.Locals (see Register).
 func (h *FraudAPIHandler) GetTransactionDetails(c *fiber.Ctx) error {
 	reference := c.Params("reference")
-	if reference == "" {
-		return fiber.NewError(fiber.StatusBadRequest, "transaction reference is required")
+	transaction := c.Locals(middleware.TransactionLocalsKey).(*services.TransactionResponse)
+	baseURL := strings.TrimRight(h.svc.TransactionServiceURL(), "/")
+
+	links := map[string]hal.Link{
+		"self":                             {Href: "/fraud/transactions/" + reference},
+		"decisions":                        {Href: "/fraud/transactions/" + reference + "/decisions"},
+		"similar":                          {Href: "/fraud/transactions/" + reference + "/similar"},
+		"related-transactions-by-customer": {Href: fmt.Sprintf("%s/transactions?customer_id=%d", baseURL, transaction.CustomerID)},
+		"merchant":                         {Href: fmt.Sprintf("%s/merchants/%d", baseURL, transaction.MerchantID)},
+		"refund":                           {Href: fmt.Sprintf("%s/transactions/%s/refund", baseURL, reference)},
+	}
+	embedded := map[string]interface{}{
+		// No decision log is persisted yet; this is where past decisions for
+		// reference will surface once one exists (see GetTransactionDecisions).
+		"decisions": []interface{}{},
 	}
 
-	transaction, err := h.svc.GetTransactionDetailsByReference(c.Context(), reference)
-	if err != nil {
-		// Differentiate between "not found" and other errors
-		if err.Error() == fmt.Sprintf("transaction with reference %s not found", reference) {
-			return fiber.NewError(fiber.StatusNotFound, err.Error())
+	if h.challengeManager != nil {
+		if challenge, err := h.challengeManager.ByTransactionRef(c.Context(), reference); err == nil {
+			links["challenge"] = hal.Link{Href: "/fraud/challenges/" + challenge.ID}
+			embedded["challenge"] = challenge
 		}
-		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
 
-	return c.JSON(transaction)
+	return hal.SendHAL(c, fiber.StatusOK, transaction, links, embedded)
+}
+
+// GetTransactionDecisions handles GET /fraud/transactions/:reference/decisions.
+// No decision log is persisted yet, so this always returns an empty trail;
+// it is registered now so GetTransactionDetails's "decisions" link resolves
+// to a real endpoint instead of a dead one.
+func (h *FraudAPIHandler) GetTransactionDecisions(c *fiber.Ctx) error {
+	reference := c.Params("reference")
+	return hal.SendHAL(c, fiber.StatusOK, fiber.Map{"decisions": []interface{}{}}, map[string]hal.Link{
+		"self":        {Href: "/fraud/transactions/" + reference + "/decisions"},
+		"transaction": {Href: "/fraud/transactions/" + reference},
+	}, nil)
+}
+
+// GetSimilarTransactions handles GET /fraud/transactions/:reference/similar.
+// No similarity index is built yet, so this always returns an empty set; it
+// is registered now so GetTransactionDetails's "similar" link resolves to a
+// real endpoint instead of a dead one.
+func (h *FraudAPIHandler) GetSimilarTransactions(c *fiber.Ctx) error {
+	reference := c.Params("reference")
+	return hal.SendHAL(c, fiber.StatusOK, fiber.Map{"similar_transactions": []interface{}{}}, map[string]hal.Link{
+		"self":        {Href: "/fraud/transactions/" + reference + "/similar"},
+		"transaction": {Href: "/fraud/transactions/" + reference},
+	}, nil)
+}
+
+// GetChallenge handles GET /fraud/challenges/:id, returned as a HAL document
+// so GetTransactionDetails's "challenge" link resolves to a real endpoint.
+func (h *FraudAPIHandler) GetChallenge(c *fiber.Ctx) error {
+	if h.challengeManager == nil {
+		return fiber.NewError(fiber.StatusNotFound, "step-up verification is not configured")
+	}
+	challengeID := c.Params("id")
+
+	challenge, err := h.challengeManager.Get(c.Context(), challengeID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	}
+
+	return hal.SendHAL(c, fiber.StatusOK, challenge, map[string]hal.Link{
+		"self": {Href: "/fraud/challenges/" + challengeID},
+	}, nil)
+}
+
+// ListModels handles the admin request to list currently loaded
+// model-backed scorers and their feature schemas.
+func (h *FraudAPIHandler) ListModels(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"models": h.svc.Models(),
+	})
+}
+
+// ReloadRules handles the admin request to hot-reload the rules file,
+// returning any parse error instead of swapping in a broken rule set.
+func (h *FraudAPIHandler) ReloadRules(c *fiber.Ctx) error {
+	if h.ruleLoader == nil {
+		return fiber.NewError(fiber.StatusNotFound, "rule hot-reload is not configured")
+	}
+	if err := h.ruleLoader.Load(); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	return c.JSON(fiber.Map{
+		"version": h.ruleLoader.Version(),
+	})
+}
+
+// ConsumerMetrics handles the admin request to report the async fraud
+// evaluation worker's processing counters, for monitoring consumer lag and
+// error rates when EVENTS_DRIVER is configured.
+func (h *FraudAPIHandler) ConsumerMetrics(c *fiber.Ctx) error {
+	return c.JSON(h.svc.AsyncMetrics())
+}
+
+// ReplayEvents handles the admin request to redeliver outbox events created
+// between the from/to RFC3339 query timestamps, e.g. after a downstream
+// consumer outage that needs a window of decision events resent. Requires a
+// dispatcher (see WithDispatcher), which in turn requires POSTGRES_DSN to be
+// configured.
+func (h *FraudAPIHandler) ReplayEvents(c *fiber.Ctx) error {
+	if h.dispatcher == nil {
+		return fiber.NewError(fiber.StatusNotFound, "event replay is not configured")
+	}
+
+	from, err := time.Parse(time.RFC3339, c.Query("from"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "from must be an RFC3339 timestamp")
+	}
+	to, err := time.Parse(time.RFC3339, c.Query("to"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "to must be an RFC3339 timestamp")
+	}
+
+	redelivered, err := h.dispatcher.Redeliver(c.Context(), from, to)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(fiber.Map{"redelivered": redelivered})
 }
 
 // Register adds the fraud-related routes to the Fiber app.
 func (h *FraudAPIHandler) Register(app *fiber.App) {
 	fraudGroup := app.Group("/fraud")
-	fraudGroup.Use(limiter.New(limiter.Config{
-		Max:        5,               // Allow 5 requests
-		Expiration: 1 * time.Second, // per 1 second
-		KeyGenerator: func(c *fiber.Ctx) string {
-			return c.Get("X-API-Key") // Rate limit per API key
-		},
-	}))
-	fraudGroup.Post("/check-transaction", h.CheckTransaction)
-	fraudGroup.Post("/track-payment-link", h.TrackPaymentLink)
-	fraudGroup.Post("/validate-payment-channel", h.ValidatePaymentChannel)
-	fraudGroup.Get("/transactions/:reference", h.GetTransactionDetails) // New route
+	fraudGroup.Use(requestid.New())
+	if h.rateLimiter != nil {
+		fraudGroup.Use(h.rateLimiter)
+	} else {
+		fraudGroup.Use(limiter.New(limiter.Config{
+			Max:        5,               // Allow 5 requests
+			Expiration: 1 * time.Second, // per 1 second
+			KeyGenerator: func(c *fiber.Ctx) string {
+				return c.Get("X-API-Key") // Rate limit per API key
+			},
+		}))
+	}
+	fraudGroup.Post("/check-transaction", middleware.BindAndValidate(dto.CheckTransactionRequest{}), h.CheckTransaction)
+	fraudGroup.Post("/check-transaction/async", middleware.BindAndValidate(dto.CheckTransactionRequest{}), h.CheckTransactionAsync)
+	fraudGroup.Post("/payment-links", h.IssuePaymentLink)
+	fraudGroup.Post("/track-payment-link", middleware.BindAndValidate(dto.TrackPaymentLinkRequest{}), h.TrackPaymentLink)
+	fraudGroup.Post("/validate-payment-channel", middleware.BindAndValidate(dto.ValidatePaymentChannelRequest{}), h.ValidatePaymentChannel)
+	fraudGroup.Post("/challenges/:id/start", middleware.BindAndValidate(dto.StartChallengeRequest{}), h.StartChallenge)
+	fraudGroup.Post("/challenges/:id/verify", middleware.BindAndValidate(dto.VerifyChallengeRequest{}), h.VerifyChallenge)
+	fraudGroup.Get("/challenges/:id", h.GetChallenge)
+
+	txGroup := fraudGroup.Group("/transactions/:reference", middleware.CheckTransactionExists(h.svc))
+	txGroup.Get("", h.GetTransactionDetails)
+	txGroup.Get("/decisions", h.GetTransactionDecisions)
+	txGroup.Get("/similar", h.GetSimilarTransactions)
+
+	adminGroup := app.Group("/fraud/admin")
+	adminGroup.Get("/models", h.ListModels)
+	adminGroup.Post("/rules/reload", h.ReloadRules)
+	adminGroup.Get("/consumer-metrics", h.ConsumerMetrics)
+	adminGroup.Post("/events/replay", h.ReplayEvents)
 }
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kodra-pay/fraud-service/internal/middleware"
+	"github.com/kodra-pay/fraud-service/internal/ratelimit"
+)
+
+// MerchantAPIHandler exposes admin operations on rate-limit tiers over
+// HTTP, so an operator can rotate/revoke a merchant's API key or adjust its
+// tier without redeploying.
+type MerchantAPIHandler struct {
+	manager *ratelimit.Manager
+	roles   []middleware.APIKeyIdentity
+}
+
+// NewMerchantAPIHandler creates a new instance of MerchantAPIHandler. roles
+// authenticates the /admin/merchants API; a nil or empty roles leaves it
+// unprotected, which should only happen in local development.
+func NewMerchantAPIHandler(manager *ratelimit.Manager, roles []middleware.APIKeyIdentity) *MerchantAPIHandler {
+	return &MerchantAPIHandler{manager: manager, roles: roles}
+}
+
+// UpdateTier handles adjusting a merchant's tier and rps/burst/daily-quota
+// limits, identified by its current plaintext API key.
+func (h *MerchantAPIHandler) UpdateTier(c *fiber.Ctx) error {
+	var req struct {
+		APIKey                string `json:"api_key"`
+		Tier                  string `json:"tier"`
+		RPS                   int    `json:"rps"`
+		Burst                 int    `json:"burst"`
+		DailyQuota            int64  `json:"daily_quota"`
+		ShadowOnQuotaExceeded bool   `json:"shadow_on_quota_exceeded"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if req.APIKey == "" || req.Tier == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "api_key and tier are required")
+	}
+
+	apiKeyHash := ratelimit.HashAPIKey(req.APIKey)
+	if err := h.manager.AdjustTier(c.Context(), apiKeyHash, req.Tier, req.RPS, req.Burst, req.DailyQuota, req.ShadowOnQuotaExceeded); err != nil {
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	}
+	return c.JSON(fiber.Map{"status": "updated"})
+}
+
+// RotateKey handles retiring a merchant's current API key in favor of a
+// freshly generated one, returning the new plaintext key, e.g. after it was
+// accidentally leaked.
+func (h *MerchantAPIHandler) RotateKey(c *fiber.Ctx) error {
+	var req struct {
+		APIKey string `json:"api_key"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if req.APIKey == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "api_key is required")
+	}
+
+	newKey, err := h.manager.RotateKey(c.Context(), req.APIKey)
+	if err != nil {
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	}
+	return c.JSON(fiber.Map{"api_key": newKey})
+}
+
+// RevokeKey handles rejecting a merchant's API key outright, rather than
+// merely rate-limiting it to zero.
+func (h *MerchantAPIHandler) RevokeKey(c *fiber.Ctx) error {
+	var req struct {
+		APIKey string `json:"api_key"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if req.APIKey == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "api_key is required")
+	}
+
+	if err := h.manager.Revoke(c.Context(), req.APIKey); err != nil {
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	}
+	return c.JSON(fiber.Map{"status": "revoked"})
+}
+
+// Register adds the merchant admin routes to the Fiber app, under a
+// separate group from /fraud/admin since it manages access to the API
+// rather than the fraud engine itself.
+func (h *MerchantAPIHandler) Register(app *fiber.App) {
+	merchantGroup := app.Group("/admin/merchants")
+	if len(h.roles) > 0 {
+		merchantGroup.Use(middleware.RoleAuth(h.roles))
+	}
+	merchantGroup.Post("/tier", h.UpdateTier)
+	merchantGroup.Post("/rotate-key", h.RotateKey)
+	merchantGroup.Post("/revoke-key", h.RevokeKey)
+}
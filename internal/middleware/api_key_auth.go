@@ -4,6 +4,10 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
+// roleLocalsKey is the fiber.Ctx locals key RoleAuth stores the caller's
+// role under, for RequireRole to read back downstream.
+const roleLocalsKey = "fraud_api_key_role"
+
 // APIKeyAuth is a middleware that checks for a valid API key.
 func APIKeyAuth(apiKey string) fiber.Handler {
 	return func(c *fiber.Ctx) error {
@@ -23,4 +27,47 @@ func APIKeyAuth(apiKey string) fiber.Handler {
 
 		return c.Next()
 	}
+}
+
+// APIKeyIdentity binds an API key to the role it authenticates as, so a
+// single shared secret (APIKeyAuth) can be replaced with per-caller roles
+// where an endpoint needs RBAC, e.g. the analyst-only case management API.
+type APIKeyIdentity struct {
+	Key  string
+	Role string
+}
+
+// RoleAuth extends APIKeyAuth with role claims: it authenticates the
+// request against identities and stores the matched role in c.Locals for
+// RequireRole to check downstream.
+func RoleAuth(identities []APIKeyIdentity) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		providedAPIKey := c.Get("X-API-Key")
+		if providedAPIKey == "" {
+			return fiber.NewError(fiber.StatusUnauthorized, "API key missing")
+		}
+
+		for _, identity := range identities {
+			if identity.Key == providedAPIKey {
+				c.Locals(roleLocalsKey, identity.Role)
+				return c.Next()
+			}
+		}
+
+		return fiber.NewError(fiber.StatusUnauthorized, "Invalid API key")
+	}
+}
+
+// RequireRole only allows the request through if RoleAuth assigned it one of
+// roles. It must run after RoleAuth.
+func RequireRole(roles ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		role, _ := c.Locals(roleLocalsKey).(string)
+		for _, allowed := range roles {
+			if role == allowed {
+				return c.Next()
+			}
+		}
+		return fiber.NewError(fiber.StatusForbidden, "insufficient role for this operation")
+	}
 }
\ No newline at end of file
@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kodra-pay/fraud-service/internal/events"
+	"github.com/kodra-pay/fraud-service/internal/ratelimit"
+)
+
+// MerchantLocalsKey is the fiber.Ctx locals key RateLimit stores the
+// resolved ratelimit.Merchant under, for handlers that need it.
+const MerchantLocalsKey = "rate_limit_merchant"
+
+// ShadowModeLocalsKey is the fiber.Ctx locals key RateLimit sets to true
+// when a request was let through past its daily quota because its merchant
+// is shadow-downgraded, so FraudAPIHandler.CheckTransaction can mark the
+// resulting decision advisory-only instead of trusting it outright.
+const ShadowModeLocalsKey = "rate_limit_shadow_mode"
+
+// QuotaExceededEventType is published whenever a key exceeds its
+// DailyQuota, whether or not shadow mode lets the request through.
+const QuotaExceededEventType = "quota.exceeded"
+
+// quotaExceededPayload is the Event.Data published for QuotaExceededEventType.
+type quotaExceededPayload struct {
+	APIKeyHash string `json:"api_key_hash"`
+	Tier       string `json:"tier"`
+	DailyQuota int64  `json:"daily_quota"`
+	ShadowMode bool   `json:"shadow_mode"`
+}
+
+// RateLimit enforces a per-API-key tiered limit: the request's X-API-Key is
+// hashed and looked up in merchants for its rps/burst/daily quota, then
+// checked against limiter's Redis-backed sliding window. X-RateLimit-Limit,
+// X-RateLimit-Remaining, and X-RateLimit-Reset are set on every response;
+// a request denied for exceeding rps/burst additionally gets Retry-After
+// and a 429. A request that exceeds its daily quota is hard-blocked the
+// same way unless Merchant.ShadowOnQuotaExceeded is set, in which case it's
+// let through with ShadowModeLocalsKey set instead. emitter may be nil, in
+// which case quota.exceeded is not published.
+func RateLimit(merchants ratelimit.MerchantRepository, limiter *ratelimit.Limiter, emitter *events.AsyncEmitter) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		apiKey := c.Get("X-API-Key")
+		if apiKey == "" {
+			return fiber.NewError(fiber.StatusUnauthorized, "API key missing")
+		}
+
+		merchant, err := merchants.Get(c.Context(), ratelimit.HashAPIKey(apiKey))
+		if err != nil {
+			return fiber.NewError(fiber.StatusUnauthorized, "Invalid API key")
+		}
+		c.Locals(MerchantLocalsKey, merchant)
+
+		result, err := limiter.Allow(c.Context(), merchant)
+		if err != nil {
+			log.Printf("ratelimit: failed to check limit for merchant, failing open: %v", err)
+			return c.Next()
+		}
+
+		c.Set("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Set("X-RateLimit-Reset", strconv.FormatInt(result.ResetAt.Unix(), 10))
+
+		if result.QuotaExceeded {
+			emitQuotaExceeded(emitter, merchant)
+			if merchant.ShadowOnQuotaExceeded {
+				c.Locals(ShadowModeLocalsKey, true)
+				return c.Next()
+			}
+		}
+
+		if !result.Allowed {
+			c.Set("Retry-After", strconv.Itoa(int(result.RetryAfter/time.Second)))
+			return fiber.NewError(fiber.StatusTooManyRequests, "rate limit exceeded")
+		}
+
+		return c.Next()
+	}
+}
+
+// emitQuotaExceeded publishes QuotaExceededEventType for merchant. It is a
+// no-op when emitter is nil.
+func emitQuotaExceeded(emitter *events.AsyncEmitter, merchant ratelimit.Merchant) {
+	if emitter == nil {
+		return
+	}
+	event, err := events.NewEvent(QuotaExceededEventType, quotaExceededPayload{
+		APIKeyHash: merchant.APIKeyHash,
+		Tier:       merchant.Tier,
+		DailyQuota: merchant.DailyQuota,
+		ShadowMode: merchant.ShadowOnQuotaExceeded,
+	})
+	if err != nil {
+		log.Printf("ratelimit: failed to encode %s event: %v", QuotaExceededEventType, err)
+		return
+	}
+	emitter.Emit(QuotaExceededEventType, event)
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kodra-pay/fraud-service/internal/services"
+)
+
+// TransactionLocalsKey is the fiber.Ctx locals key CheckTransactionExists
+// stores the resolved transaction under, for the handler to read back
+// without querying the transaction service a second time.
+const TransactionLocalsKey = "transaction"
+
+// CheckTransactionExists resolves the :reference route param against svc,
+// returning 404 when svc reports services.ErrTransactionNotFound instead of
+// the brittle err.Error() string-matching this used to require, and stashes
+// the resolved transaction in c.Locals(TransactionLocalsKey) for the
+// handler.
+func CheckTransactionExists(svc *services.FraudService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		reference := c.Params("reference")
+		if reference == "" {
+			return fiber.NewError(fiber.StatusBadRequest, "transaction reference is required")
+		}
+
+		transaction, err := svc.GetTransactionDetailsByReference(c.Context(), reference)
+		if err != nil {
+			if errors.Is(err, services.ErrTransactionNotFound) {
+				return fiber.NewError(fiber.StatusNotFound, err.Error())
+			}
+			return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		}
+
+		c.Locals(TransactionLocalsKey, transaction)
+		return c.Next()
+	}
+}
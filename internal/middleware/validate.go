@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+// validatedBodyLocalsKey is the fiber.Ctx locals key BindAndValidate stores
+// the decoded, validated request body under.
+const validatedBodyLocalsKey = "validated_body"
+
+// validate is shared across requests; validator.Validate is safe for
+// concurrent use once built, and struct tag caching makes a singleton
+// meaningfully cheaper than constructing one per request.
+var validate = validator.New()
+
+// BindAndValidate parses the request body into a new value of the same type
+// as model, runs struct validation (validate:"required", "gt=0", "iso4217",
+// "url", etc.), and stores the result in c.Locals for the handler to
+// retrieve with ValidatedBody. model is only used for its type; pass a zero
+// value, e.g. BindAndValidate(dto.CheckTransactionRequest{}).
+//
+// On a parse or validation failure it writes the 400 response itself and
+// returns an error so the handler is never reached.
+func BindAndValidate(model interface{}) fiber.Handler {
+	modelType := reflect.TypeOf(model)
+	if modelType.Kind() == reflect.Ptr {
+		modelType = modelType.Elem()
+	}
+
+	return func(c *fiber.Ctx) error {
+		body := reflect.New(modelType).Interface()
+		if err := c.BodyParser(body); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+		}
+
+		if err := validate.Struct(body); err != nil {
+			return writeValidationError(c, err)
+		}
+
+		c.Locals(validatedBodyLocalsKey, body)
+		return c.Next()
+	}
+}
+
+// ValidatedBody retrieves the struct BindAndValidate decoded and validated
+// for this request. Callers type-assert to the concrete pointer type the
+// route was bound with, e.g.
+// req := middleware.ValidatedBody(c).(*dto.CheckTransactionRequest).
+func ValidatedBody(c *fiber.Ctx) interface{} {
+	return c.Locals(validatedBodyLocalsKey)
+}
+
+// fieldValidationError reports one failed validation tag, shaped so a
+// client can tell exactly which field and rule it needs to fix.
+type fieldValidationError struct {
+	Field string `json:"field"`
+	Tag   string `json:"tag"`
+	Value string `json:"value,omitempty"`
+}
+
+func writeValidationError(c *fiber.Ctx, err error) error {
+	validationErrs, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	fields := make([]fieldValidationError, 0, len(validationErrs))
+	for _, fe := range validationErrs {
+		fields = append(fields, fieldValidationError{
+			Field: fe.Field(),
+			Tag:   fe.Tag(),
+			Value: fmt.Sprintf("%v", fe.Value()),
+		})
+	}
+
+	return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		"error":  "validation failed",
+		"fields": fields,
+	})
+}
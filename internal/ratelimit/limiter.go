@@ -0,0 +1,142 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// rpsIncrementAndTrimScript records one request at the current time,
+// trims anything older than the trailing second, and returns the
+// resulting count, the same atomic record-then-trim pattern
+// repository.RedisVelocityRepository uses for velocity rules.
+//
+// KEYS[1] = sorted set key
+// ARGV[1] = score (unix nanos)
+// ARGV[2] = member (unique per request, to avoid collisions on the same nanosecond)
+// ARGV[3] = oldest score to retain (unix nanos)
+// ARGV[4] = key TTL in milliseconds, so an idle key doesn't linger forever
+const rpsIncrementAndTrimScript = `
+redis.call('ZADD', KEYS[1], ARGV[1], ARGV[2])
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', ARGV[3])
+redis.call('PEXPIRE', KEYS[1], ARGV[4])
+return redis.call('ZCARD', KEYS[1])
+`
+
+// quotaTTL bounds how long a day's quota counter key lives in Redis; it
+// must outlast the day it counts so a request made just before midnight UTC
+// isn't reset mid-count, but a multi-day lifetime isn't worth the memory.
+const quotaTTL = 26 * time.Hour
+
+// Result is the outcome of Limiter.Allow, carrying everything the HTTP
+// layer needs to set X-RateLimit-* / Retry-After and decide whether to
+// serve the request.
+type Result struct {
+	Allowed       bool
+	Limit         int
+	Remaining     int
+	ResetAt       time.Time
+	RetryAfter    time.Duration
+	QuotaExceeded bool
+}
+
+// Limiter enforces a per-key requests-per-second sliding window (RPS+Burst)
+// plus a calendar-day quota, both backed by Redis so the limit holds across
+// instances.
+type Limiter struct {
+	client *redis.Client
+}
+
+// NewLimiter creates a Limiter backed by client.
+func NewLimiter(client *redis.Client) *Limiter {
+	return &Limiter{client: client}
+}
+
+// Allow records one request for m.APIKeyHash and reports whether it's
+// within m's RPS+Burst window and DailyQuota.
+func (l *Limiter) Allow(ctx context.Context, m Merchant) (Result, error) {
+	now := time.Now()
+
+	rpsCount, err := l.recordRPS(ctx, m.APIKeyHash, now)
+	if err != nil {
+		return Result{}, err
+	}
+	quotaCount, resetAt, err := l.incrementQuota(ctx, m.APIKeyHash, now)
+	if err != nil {
+		return Result{}, err
+	}
+
+	limit := m.RPS + m.Burst
+	result := Result{
+		Limit:     limit,
+		Remaining: limit - int(rpsCount),
+		ResetAt:   resetAt,
+	}
+	if result.Remaining < 0 {
+		result.Remaining = 0
+	}
+
+	if m.DailyQuota > 0 && quotaCount > m.DailyQuota {
+		result.QuotaExceeded = true
+		result.RetryAfter = resetAt.Sub(now)
+		return result, nil
+	}
+
+	if int(rpsCount) > limit {
+		result.RetryAfter = time.Second
+		return result, nil
+	}
+
+	result.Allowed = true
+	return result, nil
+}
+
+func (l *Limiter) recordRPS(ctx context.Context, apiKeyHash string, now time.Time) (int64, error) {
+	key := "ratelimit:rps:" + apiKeyHash
+	member := fmt.Sprintf("%d:%s", now.UnixNano(), randomMember())
+	oldest := now.Add(-time.Second).UnixNano()
+
+	count, err := l.client.Eval(ctx, rpsIncrementAndTrimScript, []string{key}, now.UnixNano(), member, oldest, time.Second.Milliseconds()).Result()
+	if err != nil {
+		return 0, fmt.Errorf("ratelimit: failed to record request for %s: %w", apiKeyHash, err)
+	}
+	return toInt64(count), nil
+}
+
+func (l *Limiter) incrementQuota(ctx context.Context, apiKeyHash string, now time.Time) (int64, time.Time, error) {
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	resetAt := dayStart.Add(24 * time.Hour)
+	key := fmt.Sprintf("ratelimit:quota:%s:%s", apiKeyHash, dayStart.Format("2006-01-02"))
+
+	count, err := l.client.Incr(ctx, key).Result()
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("ratelimit: failed to increment daily quota for %s: %w", apiKeyHash, err)
+	}
+	if count == 1 {
+		if err := l.client.Expire(ctx, key, quotaTTL).Err(); err != nil {
+			return 0, time.Time{}, fmt.Errorf("ratelimit: failed to set quota key expiry for %s: %w", apiKeyHash, err)
+		}
+	}
+	return count, resetAt, nil
+}
+
+func toInt64(v interface{}) int64 {
+	switch n := v.(type) {
+	case int64:
+		return n
+	default:
+		return 0
+	}
+}
+
+// randomMember returns a short random suffix so two requests in the same
+// nanosecond don't collide in the sorted set.
+func randomMember() string {
+	buf := make([]byte, 4)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
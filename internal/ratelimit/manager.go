@@ -0,0 +1,68 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// Manager is the admin-facing entry point onto a MerchantRepository:
+// rotating/revoking keys and adjusting tiers, so an operator can manage
+// rate limits without redeploying.
+type Manager struct {
+	repo MerchantRepository
+}
+
+// NewManager creates a Manager backed by repo.
+func NewManager(repo MerchantRepository) *Manager {
+	return &Manager{repo: repo}
+}
+
+// Get returns the merchant for apiKeyHash.
+func (m *Manager) Get(ctx context.Context, apiKeyHash string) (Merchant, error) {
+	return m.repo.Get(ctx, apiKeyHash)
+}
+
+// AdjustTier updates apiKeyHash's tier and limits in place.
+func (m *Manager) AdjustTier(ctx context.Context, apiKeyHash, tier string, rps, burst int, dailyQuota int64, shadowOnQuotaExceeded bool) error {
+	return m.repo.UpdateTier(ctx, apiKeyHash, tier, rps, burst, dailyQuota, shadowOnQuotaExceeded)
+}
+
+// RotateKey generates a new API key for the merchant currently identified by
+// oldAPIKey, retiring oldAPIKey so a leaked key can't be used again. Returns
+// the new plaintext key; only its hash is ever persisted.
+func (m *Manager) RotateKey(ctx context.Context, oldAPIKey string) (string, error) {
+	newKey, err := generateAPIKey()
+	if err != nil {
+		return "", err
+	}
+	if err := m.repo.RotateKey(ctx, HashAPIKey(oldAPIKey), HashAPIKey(newKey)); err != nil {
+		return "", err
+	}
+	return newKey, nil
+}
+
+// Revoke deletes the merchant identified by apiKey outright, so the key is
+// rejected rather than merely rate-limited to zero.
+func (m *Manager) Revoke(ctx context.Context, apiKey string) error {
+	return m.repo.Revoke(ctx, HashAPIKey(apiKey))
+}
+
+// HashAPIKey returns the hex-encoded SHA-256 of key, the form merchants are
+// keyed on so a leaked repository/log never exposes a usable key.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// generateAPIKey returns a new random API key, prefixed like challenges'
+// "chal_" IDs so a key is recognizable by sight.
+func generateAPIKey() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("ratelimit: failed to generate API key: %w", err)
+	}
+	return "key_" + hex.EncodeToString(buf), nil
+}
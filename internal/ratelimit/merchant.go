@@ -0,0 +1,208 @@
+// Package ratelimit implements per-API-key tiered rate limiting: a
+// Redis-backed sliding-window request limiter plus a calendar-day quota,
+// both configured per key via a MerchantRepository instead of the single
+// fixed limit every caller used to share.
+package ratelimit
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+)
+
+// Merchant is a rate-limit tier: the requests-per-second sustained rate
+// (RPS), the short burst allowed above it, and the calendar-day quota a key
+// is entitled to.
+type Merchant struct {
+	APIKeyHash string
+	Tier       string
+	RPS        int
+	Burst      int
+	DailyQuota int64
+
+	// ShadowOnQuotaExceeded, when true, lets a key keep calling
+	// CheckTransaction past its DailyQuota instead of being hard-blocked
+	// with 429: the decision is still computed but FraudDecision.AdvisoryOnly
+	// is set, so a caller mid-incident isn't cut off outright.
+	ShadowOnQuotaExceeded bool
+}
+
+// MerchantRepository persists merchants and the admin operations
+// (handlers.MerchantAPIHandler) applies to them.
+type MerchantRepository interface {
+	Get(ctx context.Context, apiKeyHash string) (Merchant, error)
+	Create(ctx context.Context, m Merchant) error
+	// UpdateTier adjusts tier and its limits, leaving everything else
+	// (including the API key) untouched.
+	UpdateTier(ctx context.Context, apiKeyHash, tier string, rps, burst int, dailyQuota int64, shadowOnQuotaExceeded bool) error
+	// RotateKey replaces oldAPIKeyHash with newAPIKeyHash on the same
+	// merchant, so a leaked key can be retired without losing its tier.
+	RotateKey(ctx context.Context, oldAPIKeyHash, newAPIKeyHash string) error
+	// Revoke deletes the merchant, so the key is rejected outright rather
+	// than merely rate-limited to zero.
+	Revoke(ctx context.Context, apiKeyHash string) error
+}
+
+// InMemoryMerchantRepository is a process-local MerchantRepository, useful
+// for tests and local development without Postgres.
+type InMemoryMerchantRepository struct {
+	mu        sync.Mutex
+	merchants map[string]Merchant
+}
+
+// NewInMemoryMerchantRepository creates an empty in-memory repository.
+func NewInMemoryMerchantRepository() *InMemoryMerchantRepository {
+	return &InMemoryMerchantRepository{merchants: make(map[string]Merchant)}
+}
+
+// Get returns the merchant for apiKeyHash.
+func (r *InMemoryMerchantRepository) Get(_ context.Context, apiKeyHash string) (Merchant, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	m, ok := r.merchants[apiKeyHash]
+	if !ok {
+		return Merchant{}, fmt.Errorf("ratelimit: no merchant for API key")
+	}
+	return m, nil
+}
+
+// Create stores m, keyed by its APIKeyHash.
+func (r *InMemoryMerchantRepository) Create(_ context.Context, m Merchant) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.merchants[m.APIKeyHash] = m
+	return nil
+}
+
+// UpdateTier adjusts the tier and limits for apiKeyHash.
+func (r *InMemoryMerchantRepository) UpdateTier(_ context.Context, apiKeyHash, tier string, rps, burst int, dailyQuota int64, shadowOnQuotaExceeded bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	m, ok := r.merchants[apiKeyHash]
+	if !ok {
+		return fmt.Errorf("ratelimit: no merchant for API key hash %s", apiKeyHash)
+	}
+	m.Tier, m.RPS, m.Burst, m.DailyQuota, m.ShadowOnQuotaExceeded = tier, rps, burst, dailyQuota, shadowOnQuotaExceeded
+	r.merchants[apiKeyHash] = m
+	return nil
+}
+
+// RotateKey moves the merchant at oldAPIKeyHash to newAPIKeyHash.
+func (r *InMemoryMerchantRepository) RotateKey(_ context.Context, oldAPIKeyHash, newAPIKeyHash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	m, ok := r.merchants[oldAPIKeyHash]
+	if !ok {
+		return fmt.Errorf("ratelimit: no merchant for API key hash %s", oldAPIKeyHash)
+	}
+	delete(r.merchants, oldAPIKeyHash)
+	m.APIKeyHash = newAPIKeyHash
+	r.merchants[newAPIKeyHash] = m
+	return nil
+}
+
+// Revoke deletes the merchant at apiKeyHash.
+func (r *InMemoryMerchantRepository) Revoke(_ context.Context, apiKeyHash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.merchants[apiKeyHash]; !ok {
+		return fmt.Errorf("ratelimit: no merchant for API key hash %s", apiKeyHash)
+	}
+	delete(r.merchants, apiKeyHash)
+	return nil
+}
+
+// PostgresMerchantRepository implements MerchantRepository against a
+// merchants table:
+//
+//	CREATE TABLE merchants (
+//		api_key_hash             TEXT PRIMARY KEY,
+//		tier                     TEXT NOT NULL,
+//		rps                      INTEGER NOT NULL,
+//		burst                    INTEGER NOT NULL DEFAULT 0,
+//		daily_quota              BIGINT NOT NULL,
+//		shadow_on_quota_exceeded BOOLEAN NOT NULL DEFAULT false
+//	);
+type PostgresMerchantRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresMerchantRepository wraps an existing *sql.DB; the service is
+// expected to own connection pooling and migrations.
+func NewPostgresMerchantRepository(db *sql.DB) *PostgresMerchantRepository {
+	return &PostgresMerchantRepository{db: db}
+}
+
+// Get returns the merchant for apiKeyHash.
+func (r *PostgresMerchantRepository) Get(ctx context.Context, apiKeyHash string) (Merchant, error) {
+	var m Merchant
+	err := r.db.QueryRowContext(ctx,
+		`SELECT api_key_hash, tier, rps, burst, daily_quota, shadow_on_quota_exceeded FROM merchants WHERE api_key_hash = $1`,
+		apiKeyHash,
+	).Scan(&m.APIKeyHash, &m.Tier, &m.RPS, &m.Burst, &m.DailyQuota, &m.ShadowOnQuotaExceeded)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return Merchant{}, fmt.Errorf("ratelimit: no merchant for API key: %w", err)
+		}
+		return Merchant{}, fmt.Errorf("ratelimit: failed to look up merchant: %w", err)
+	}
+	return m, nil
+}
+
+// Create inserts m.
+func (r *PostgresMerchantRepository) Create(ctx context.Context, m Merchant) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO merchants (api_key_hash, tier, rps, burst, daily_quota, shadow_on_quota_exceeded)
+		 VALUES ($1, $2, $3, $4, $5, $6)`,
+		m.APIKeyHash, m.Tier, m.RPS, m.Burst, m.DailyQuota, m.ShadowOnQuotaExceeded,
+	)
+	if err != nil {
+		return fmt.Errorf("ratelimit: failed to insert merchant: %w", err)
+	}
+	return nil
+}
+
+// UpdateTier adjusts the tier and limits for apiKeyHash.
+func (r *PostgresMerchantRepository) UpdateTier(ctx context.Context, apiKeyHash, tier string, rps, burst int, dailyQuota int64, shadowOnQuotaExceeded bool) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE merchants SET tier = $1, rps = $2, burst = $3, daily_quota = $4, shadow_on_quota_exceeded = $5 WHERE api_key_hash = $6`,
+		tier, rps, burst, dailyQuota, shadowOnQuotaExceeded, apiKeyHash,
+	)
+	if err != nil {
+		return fmt.Errorf("ratelimit: failed to update merchant: %w", err)
+	}
+	return requireRowAffected(result, apiKeyHash)
+}
+
+// RotateKey moves the merchant at oldAPIKeyHash to newAPIKeyHash.
+func (r *PostgresMerchantRepository) RotateKey(ctx context.Context, oldAPIKeyHash, newAPIKeyHash string) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE merchants SET api_key_hash = $1 WHERE api_key_hash = $2`,
+		newAPIKeyHash, oldAPIKeyHash,
+	)
+	if err != nil {
+		return fmt.Errorf("ratelimit: failed to rotate merchant key: %w", err)
+	}
+	return requireRowAffected(result, oldAPIKeyHash)
+}
+
+// Revoke deletes the merchant at apiKeyHash.
+func (r *PostgresMerchantRepository) Revoke(ctx context.Context, apiKeyHash string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM merchants WHERE api_key_hash = $1`, apiKeyHash)
+	if err != nil {
+		return fmt.Errorf("ratelimit: failed to revoke merchant: %w", err)
+	}
+	return requireRowAffected(result, apiKeyHash)
+}
+
+func requireRowAffected(result sql.Result, apiKeyHash string) error {
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("ratelimit: failed to confirm merchant update: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("ratelimit: no merchant for API key hash %s", apiKeyHash)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"sync"
+)
+
+// BlacklistKind identifies which attribute of a transaction a
+// BlacklistRepository entry covers.
+type BlacklistKind string
+
+const (
+	BlacklistKindIP      BlacklistKind = "ip"
+	BlacklistKindDevice  BlacklistKind = "device"
+	BlacklistKindCardBin BlacklistKind = "card_bin"
+)
+
+// BlacklistRepository tracks IPs, device IDs, and card BINs confirmed
+// fraudulent by the analyst case workflow, so a future transaction sharing
+// one of them can be caught up front instead of needing its own case.
+type BlacklistRepository interface {
+	Add(ctx context.Context, kind BlacklistKind, value string) error
+	Contains(ctx context.Context, kind BlacklistKind, value string) (bool, error)
+}
+
+// InMemoryBlacklistRepository is a process-local BlacklistRepository; a
+// multi-instance deployment would back this with a shared store instead
+// (e.g. Redis, matching RedisVelocityRepository).
+type InMemoryBlacklistRepository struct {
+	mu      sync.RWMutex
+	entries map[BlacklistKind]map[string]struct{}
+}
+
+// NewInMemoryBlacklistRepository creates an empty in-memory repository.
+func NewInMemoryBlacklistRepository() *InMemoryBlacklistRepository {
+	return &InMemoryBlacklistRepository{entries: make(map[BlacklistKind]map[string]struct{})}
+}
+
+// Add records value as blacklisted for kind.
+func (r *InMemoryBlacklistRepository) Add(_ context.Context, kind BlacklistKind, value string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.entries[kind] == nil {
+		r.entries[kind] = make(map[string]struct{})
+	}
+	r.entries[kind][value] = struct{}{}
+	return nil
+}
+
+// Contains reports whether value has been blacklisted for kind.
+func (r *InMemoryBlacklistRepository) Contains(_ context.Context, kind BlacklistKind, value string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.entries[kind][value]
+	return ok, nil
+}
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DeviceFingerprint is the client-supplied signal set used to assess how
+// stable/trustworthy a device is, typically gathered by a JS fingerprinting
+// snippet on checkout.
+type DeviceFingerprint struct {
+	UserAgent   string `json:"user_agent"`
+	CanvasHash  string `json:"canvas_hash"`
+	Timezone    string `json:"timezone"`
+	WebGLVendor string `json:"webgl_vendor"`
+}
+
+// DeviceInfo is the enrichment attached to a transaction's device
+// fingerprint and surfaced to rules as the "device.*" dotted attributes.
+type DeviceInfo struct {
+	StabilityScore float64 `json:"stability_score"` // 0 (suspicious) .. 1 (stable, consistent signals)
+	IsEmulator     bool    `json:"is_emulator"`
+}
+
+// Attrs converts info into the nested map dsl.Env.resolve expects for a
+// "device.*" attribute path.
+func (info DeviceInfo) Attrs() map[string]interface{} {
+	return map[string]interface{}{
+		"stability_score": info.StabilityScore,
+		"is_emulator":     info.IsEmulator,
+	}
+}
+
+// DeviceEnricher scores a client-supplied fingerprint for stability and
+// emulation.
+type DeviceEnricher interface {
+	Enrich(ctx context.Context, fingerprintJSON string) (DeviceInfo, error)
+}
+
+// HeuristicDeviceEnricher scores a DeviceFingerprint using a handful of
+// cheap heuristics rather than a trained model: missing or low-entropy
+// fields, canvas hashes known to come from headless/automation stacks, and
+// a user-agent/WebGL-vendor combination that doesn't occur on real
+// hardware, all push the stability score down and flip IsEmulator.
+type HeuristicDeviceEnricher struct{}
+
+// NewHeuristicDeviceEnricher creates a HeuristicDeviceEnricher.
+func NewHeuristicDeviceEnricher() *HeuristicDeviceEnricher {
+	return &HeuristicDeviceEnricher{}
+}
+
+// knownEmulatorCanvasHashes are canvas hashes headless/automation stacks
+// (e.g. a stock headless Chrome with no GPU) are known to render
+// identically, regardless of the real device behind them.
+var knownEmulatorCanvasHashes = map[string]struct{}{
+	"": {},
+}
+
+// Enrich parses fingerprintJSON and scores it.
+func (e *HeuristicDeviceEnricher) Enrich(_ context.Context, fingerprintJSON string) (DeviceInfo, error) {
+	var fp DeviceFingerprint
+	if err := json.Unmarshal([]byte(fingerprintJSON), &fp); err != nil {
+		return DeviceInfo{}, fmt.Errorf("device enrichment: failed to parse fingerprint: %w", err)
+	}
+
+	score := 1.0
+	isEmulator := false
+
+	if fp.UserAgent == "" {
+		score -= 0.4
+	} else if strings.Contains(strings.ToLower(fp.UserAgent), "headlesschrome") {
+		score -= 0.5
+		isEmulator = true
+	}
+
+	if _, known := knownEmulatorCanvasHashes[fp.CanvasHash]; known {
+		score -= 0.4
+		isEmulator = true
+	}
+
+	if fp.Timezone == "" {
+		score -= 0.2
+	}
+
+	if fp.WebGLVendor == "" {
+		score -= 0.2
+	} else if strings.Contains(strings.ToLower(fp.WebGLVendor), "swiftshader") || strings.Contains(strings.ToLower(fp.WebGLVendor), "llvmpipe") {
+		// Software GL renderers are the norm for headless/virtualized
+		// browsers and essentially never seen on consumer hardware.
+		score -= 0.3
+		isEmulator = true
+	}
+
+	if score < 0 {
+		score = 0
+	}
+
+	return DeviceInfo{StabilityScore: score, IsEmulator: isEmulator}, nil
+}
+
+// CachedDeviceEnricher wraps another DeviceEnricher with a Redis-backed
+// cache keyed by the raw fingerprint payload, so a repeat submission of the
+// same fingerprint within ttl skips re-scoring.
+type CachedDeviceEnricher struct {
+	next   DeviceEnricher
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// NewCachedDeviceEnricher wraps next, caching results in client for ttl.
+func NewCachedDeviceEnricher(next DeviceEnricher, client *redis.Client, ttl time.Duration) *CachedDeviceEnricher {
+	return &CachedDeviceEnricher{next: next, client: client, ttl: ttl}
+}
+
+// Enrich returns the cached DeviceInfo for fingerprintJSON if present,
+// otherwise delegates to the wrapped enricher and caches the result.
+func (c *CachedDeviceEnricher) Enrich(ctx context.Context, fingerprintJSON string) (DeviceInfo, error) {
+	key := "device_enrich:" + hashFingerprint(fingerprintJSON)
+	if cached, err := c.client.Get(ctx, key).Result(); err == nil {
+		var info DeviceInfo
+		if err := json.Unmarshal([]byte(cached), &info); err == nil {
+			return info, nil
+		}
+	}
+
+	info, err := c.next.Enrich(ctx, fingerprintJSON)
+	if err != nil {
+		return DeviceInfo{}, err
+	}
+
+	if encoded, err := json.Marshal(info); err == nil {
+		c.client.Set(ctx, key, encoded, c.ttl)
+	}
+	return info, nil
+}
+
+// hashFingerprint derives a stable, fixed-length cache key from a raw
+// fingerprint payload so the Redis key doesn't grow with the payload size.
+func hashFingerprint(fingerprintJSON string) string {
+	sum := sha256.Sum256([]byte(fingerprintJSON))
+	return hex.EncodeToString(sum[:])
+}
@@ -11,6 +11,12 @@ type TransactionRecord struct {
 	Amount    float64
 	Currency  string
 	Timestamp time.Time
+	// IPCountry, Latitude, and Longitude capture the origin IP's resolved
+	// location at the time of the transaction, for geo-velocity ("impossible
+	// travel") checks against the customer's next transaction.
+	IPCountry string
+	Latitude  float64
+	Longitude float64
 	// Add more relevant fields like merchant ID, customer ID, IP, device ID, etc.
 }
 
@@ -19,6 +25,10 @@ type FraudDataRepository interface {
 	GetTransactionHistory(ctx context.Context, customerID string, lookback time.Duration) ([]TransactionRecord, error)
 	GetIPData(ctx context.Context, ipAddress string) (map[string]interface{}, error) // Placeholder for IP-related data
 	GetDeviceData(ctx context.Context, deviceID string) (map[string]interface{}, error) // Placeholder for device-related data
+	// RecordTransactionLocation stores the origin IP's resolved location for
+	// a processed transaction, so a later geo-velocity check has something
+	// to compare the customer's next transaction against.
+	RecordTransactionLocation(ctx context.Context, customerID string, record TransactionRecord) error
 }
 
 // InMemoryFraudDataRepository is a simple in-memory implementation of FraudDataRepository.
@@ -77,6 +87,13 @@ func (r *InMemoryFraudDataRepository) AddTransaction(customerID string, txn Tran
 	r.transactions[customerID] = append(r.transactions[customerID], txn)
 }
 
+// RecordTransactionLocation appends record to customerID's history so a
+// subsequent geo-velocity check can compare against it.
+func (r *InMemoryFraudDataRepository) RecordTransactionLocation(_ context.Context, customerID string, record TransactionRecord) error {
+	r.transactions[customerID] = append(r.transactions[customerID], record)
+	return nil
+}
+
 // AddIPData is a helper to populate the in-memory repository for testing.
 func (r *InMemoryFraudDataRepository) AddIPData(ipAddress string, data map[string]interface{}) {
 	r.ipData[ipAddress] = data
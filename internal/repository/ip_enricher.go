@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/oschwald/geoip2-golang"
+	"github.com/redis/go-redis/v9"
+)
+
+// IPInfo is the enrichment attached to a transaction's origin IP and
+// surfaced to rules as the "ip.*" dotted attributes (ip.country, ip.asn,
+// ip.is_tor, ...).
+type IPInfo struct {
+	Country   string  `json:"country"`
+	ASN       uint    `json:"asn"`
+	ISP       string  `json:"isp"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	IsVPN     bool    `json:"is_vpn"`
+	IsTor     bool    `json:"is_tor"`
+	IsHosting bool    `json:"is_hosting"`
+}
+
+// Attrs converts info into the nested map dsl.Env.resolve expects for an
+// "ip.*" attribute path.
+func (info IPInfo) Attrs() map[string]interface{} {
+	return map[string]interface{}{
+		"country":    info.Country,
+		"asn":        info.ASN,
+		"isp":        info.ISP,
+		"latitude":   info.Latitude,
+		"longitude":  info.Longitude,
+		"is_vpn":     info.IsVPN,
+		"is_tor":     info.IsTor,
+		"is_hosting": info.IsHosting,
+	}
+}
+
+// IPEnricher resolves an origin IP to geo/ASN intelligence.
+type IPEnricher interface {
+	Enrich(ctx context.Context, ip string) (IPInfo, error)
+}
+
+// MaxMindIPEnricher resolves country and location from a MaxMind
+// GeoLite2-City database, ASN/ISP from a GeoLite2-ASN database, and flags
+// Tor/VPN/hosting origins against configurable sets, so operators can keep
+// them current without a code change.
+type MaxMindIPEnricher struct {
+	mu           sync.RWMutex
+	cityDB       *geoip2.Reader
+	asnDB        *geoip2.Reader
+	cityDBPath   string
+	asnDBPath    string
+	torExitNodes map[string]struct{}
+	hostingASNs  map[uint]struct{}
+}
+
+// NewMaxMindIPEnricher opens cityDBPath (required) and asnDBPath (optional;
+// ASN/ISP enrichment is skipped if empty). torExitNodes and hostingASNs seed
+// the Tor and datacenter/VPN classification; Reload re-reads both databases
+// in place, e.g. from a periodic refresh triggered by routes.go.
+func NewMaxMindIPEnricher(cityDBPath, asnDBPath string, torExitNodes []string, hostingASNs []uint) (*MaxMindIPEnricher, error) {
+	e := &MaxMindIPEnricher{
+		cityDBPath:   cityDBPath,
+		asnDBPath:    asnDBPath,
+		torExitNodes: make(map[string]struct{}, len(torExitNodes)),
+		hostingASNs:  make(map[uint]struct{}, len(hostingASNs)),
+	}
+	for _, ip := range torExitNodes {
+		e.torExitNodes[ip] = struct{}{}
+	}
+	for _, asn := range hostingASNs {
+		e.hostingASNs[asn] = struct{}{}
+	}
+	if err := e.Reload(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Reload re-opens both MaxMind databases, picking up a newer file dropped at
+// the same path (e.g. by a cron job syncing the latest GeoLite2 release).
+func (e *MaxMindIPEnricher) Reload() error {
+	cityDB, err := geoip2.Open(e.cityDBPath)
+	if err != nil {
+		return fmt.Errorf("ip enrichment: failed to open city database %s: %w", e.cityDBPath, err)
+	}
+
+	var asnDB *geoip2.Reader
+	if e.asnDBPath != "" {
+		asnDB, err = geoip2.Open(e.asnDBPath)
+		if err != nil {
+			cityDB.Close()
+			return fmt.Errorf("ip enrichment: failed to open ASN database %s: %w", e.asnDBPath, err)
+		}
+	}
+
+	e.mu.Lock()
+	oldCity, oldASN := e.cityDB, e.asnDB
+	e.cityDB, e.asnDB = cityDB, asnDB
+	e.mu.Unlock()
+
+	if oldCity != nil {
+		oldCity.Close()
+	}
+	if oldASN != nil {
+		oldASN.Close()
+	}
+	return nil
+}
+
+// Enrich resolves ip's country, ASN/ISP, and Tor/VPN/hosting classification.
+func (e *MaxMindIPEnricher) Enrich(_ context.Context, ip string) (IPInfo, error) {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return IPInfo{}, fmt.Errorf("ip enrichment: %q is not a valid IP address", ip)
+	}
+
+	e.mu.RLock()
+	cityDB, asnDB := e.cityDB, e.asnDB
+	e.mu.RUnlock()
+
+	var info IPInfo
+	_, info.IsTor = e.torExitNodes[ip]
+
+	city, err := cityDB.City(parsed)
+	if err != nil {
+		return IPInfo{}, fmt.Errorf("ip enrichment: city lookup failed for %s: %w", ip, err)
+	}
+	info.Country = city.Country.IsoCode
+	info.Latitude = city.Location.Latitude
+	info.Longitude = city.Location.Longitude
+
+	if asnDB != nil {
+		asn, err := asnDB.ASN(parsed)
+		if err != nil {
+			return IPInfo{}, fmt.Errorf("ip enrichment: ASN lookup failed for %s: %w", ip, err)
+		}
+		info.ASN = asn.AutonomousSystemNumber
+		info.ISP = asn.AutonomousSystemOrganization
+		_, info.IsHosting = e.hostingASNs[info.ASN]
+		info.IsVPN = info.IsHosting
+	}
+
+	return info, nil
+}
+
+// Close releases the underlying MaxMind database files.
+func (e *MaxMindIPEnricher) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.cityDB != nil {
+		e.cityDB.Close()
+	}
+	if e.asnDB != nil {
+		e.asnDB.Close()
+	}
+	return nil
+}
+
+// CachedIPEnricher wraps another IPEnricher with a Redis-backed cache, so a
+// repeat lookup for the same IP within ttl skips the MaxMind database read.
+type CachedIPEnricher struct {
+	next   IPEnricher
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// NewCachedIPEnricher wraps next, caching results in client for ttl.
+func NewCachedIPEnricher(next IPEnricher, client *redis.Client, ttl time.Duration) *CachedIPEnricher {
+	return &CachedIPEnricher{next: next, client: client, ttl: ttl}
+}
+
+// Enrich returns the cached IPInfo for ip if present, otherwise delegates to
+// the wrapped enricher and caches the result.
+func (c *CachedIPEnricher) Enrich(ctx context.Context, ip string) (IPInfo, error) {
+	key := "ip_enrich:" + ip
+	if cached, err := c.client.Get(ctx, key).Result(); err == nil {
+		var info IPInfo
+		if err := json.Unmarshal([]byte(cached), &info); err == nil {
+			return info, nil
+		}
+	}
+
+	info, err := c.next.Enrich(ctx, ip)
+	if err != nil {
+		return IPInfo{}, err
+	}
+
+	if encoded, err := json.Marshal(info); err == nil {
+		c.client.Set(ctx, key, encoded, c.ttl)
+	}
+	return info, nil
+}
@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// VelocityAggregation selects how entries within a window are combined by
+// VelocityRepository.Count.
+type VelocityAggregation string
+
+const (
+	// VelocityAggregationCount counts the number of entries in the window.
+	VelocityAggregationCount VelocityAggregation = "count"
+	// VelocityAggregationSumAmount sums the amount recorded with each entry.
+	VelocityAggregationSumAmount VelocityAggregation = "sum-amount"
+	// VelocityAggregationDistinctMerchant counts distinct merchant IDs seen
+	// in the window.
+	VelocityAggregationDistinctMerchant VelocityAggregation = "distinct-merchant"
+)
+
+// VelocityRepository tracks sliding-window aggregates (count, summed amount,
+// distinct merchants) per key, e.g. customer_id, ip, device_id, or card_bin.
+// Count must filter to the caller's window without discarding anything
+// outside it, since two rules may query the same key with different
+// windows; implementations instead bound retention on Increment against a
+// fixed, implementation-wide maximum (at least as wide as the widest window
+// any rule queries) so storage doesn't grow unbounded.
+type VelocityRepository interface {
+	// Increment records a single occurrence for key at ts, carrying amount
+	// and merchant along for sum-amount and distinct-merchant aggregations.
+	Increment(ctx context.Context, key string, ts time.Time, amount float64, merchant string) error
+	// Count returns the aggregate value for key over the trailing window
+	// ending now, using agg to combine entries.
+	Count(ctx context.Context, key string, window time.Duration, agg VelocityAggregation) (float64, error)
+}
+
+// velocityIncrementAndTrimScript atomically records an entry, trims anything
+// older than the widest configured window, and returns nothing further; the
+// count itself is read back with ZCOUNT so callers can ask for any window up
+// to the trimmed retention without re-running the script.
+//
+// KEYS[1] = sorted set key
+// ARGV[1] = score (unix nanos)
+// ARGV[2] = member (unique per entry, to avoid collisions on the same nanosecond)
+// ARGV[3] = oldest score to retain (unix nanos)
+const velocityIncrementAndTrimScript = `
+redis.call('ZADD', KEYS[1], ARGV[1], ARGV[2])
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', ARGV[3])
+return redis.call('ZCARD', KEYS[1])
+`
+
+// RedisVelocityRepository implements VelocityRepository using Redis sorted
+// sets keyed by the velocity key, scored by unix-nanos. Increment and trim
+// happen atomically via velocityIncrementAndTrimScript to avoid races between
+// concurrent writers on the same key.
+type RedisVelocityRepository struct {
+	client       *redis.Client
+	maxRetention time.Duration
+}
+
+// NewRedisVelocityRepository creates a repository backed by client. Entries
+// older than maxRetention are eligible for trimming on every Increment, so it
+// should be set to at least the widest window any rule queries.
+func NewRedisVelocityRepository(client *redis.Client, maxRetention time.Duration) *RedisVelocityRepository {
+	return &RedisVelocityRepository{client: client, maxRetention: maxRetention}
+}
+
+func velocityMember(ts time.Time, amount float64, merchant string) string {
+	return fmt.Sprintf("%d:%f:%s", ts.UnixNano(), amount, merchant)
+}
+
+// Increment adds an entry for key and trims anything past maxRetention.
+func (r *RedisVelocityRepository) Increment(ctx context.Context, key string, ts time.Time, amount float64, merchant string) error {
+	oldest := ts.Add(-r.maxRetention).UnixNano()
+	member := velocityMember(ts, amount, merchant)
+	if err := r.client.Eval(ctx, velocityIncrementAndTrimScript, []string{key}, ts.UnixNano(), member, oldest).Err(); err != nil {
+		return fmt.Errorf("failed to increment velocity key %s: %w", key, err)
+	}
+	return nil
+}
+
+// Count aggregates entries for key within the trailing window.
+func (r *RedisVelocityRepository) Count(ctx context.Context, key string, window time.Duration, agg VelocityAggregation) (float64, error) {
+	cutoff := time.Now().Add(-window).UnixNano()
+	members, err := r.client.ZRangeByScore(ctx, key, &redis.ZRangeBy{Min: fmt.Sprintf("%d", cutoff), Max: "+inf"}).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count velocity key %s: %w", key, err)
+	}
+	return aggregateVelocityMembers(members, agg), nil
+}
+
+func aggregateVelocityMembers(members []string, agg VelocityAggregation) float64 {
+	switch agg {
+	case VelocityAggregationSumAmount:
+		var sum float64
+		for _, m := range members {
+			var nanos int64
+			var amount float64
+			var merchant string
+			fmt.Sscanf(m, "%d:%f:%s", &nanos, &amount, &merchant)
+			sum += amount
+		}
+		return sum
+	case VelocityAggregationDistinctMerchant:
+		seen := make(map[string]struct{})
+		for _, m := range members {
+			var nanos int64
+			var amount float64
+			var merchant string
+			fmt.Sscanf(m, "%d:%f:%s", &nanos, &amount, &merchant)
+			seen[merchant] = struct{}{}
+		}
+		return float64(len(seen))
+	case VelocityAggregationCount:
+		fallthrough
+	default:
+		return float64(len(members))
+	}
+}
+
+// defaultVelocityRetention bounds how far back InMemoryVelocityRepository
+// keeps entries, matching the widest window among fraud.DefaultRules. It
+// plays the same role maxRetention plays for RedisVelocityRepository, just
+// without a constructor argument since NewInMemoryVelocityRepository is used
+// for tests and local development rather than wired to configuration.
+const defaultVelocityRetention = 24 * time.Hour
+
+// InMemoryVelocityRepository is an in-memory VelocityRepository, useful for
+// tests and local development without a Redis instance.
+type InMemoryVelocityRepository struct {
+	mu           sync.Mutex
+	entries      map[string][]velocityEntry
+	maxRetention time.Duration
+}
+
+type velocityEntry struct {
+	ts       time.Time
+	amount   float64
+	merchant string
+}
+
+// NewInMemoryVelocityRepository creates an empty in-memory repository.
+func NewInMemoryVelocityRepository() *InMemoryVelocityRepository {
+	return &InMemoryVelocityRepository{entries: make(map[string][]velocityEntry), maxRetention: defaultVelocityRetention}
+}
+
+// Increment records an occurrence for key and trims anything past
+// maxRetention, mirroring RedisVelocityRepository so retention doesn't
+// depend on which window a Count happens to be called with.
+func (r *InMemoryVelocityRepository) Increment(_ context.Context, key string, ts time.Time, amount float64, merchant string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries[key] = append(r.entries[key], velocityEntry{ts: ts, amount: amount, merchant: merchant})
+
+	cutoff := ts.Add(-r.maxRetention)
+	kept := r.entries[key][:0]
+	for _, e := range r.entries[key] {
+		if e.ts.After(cutoff) {
+			kept = append(kept, e)
+		}
+	}
+	r.entries[key] = kept
+	return nil
+}
+
+// Count aggregates entries for key within the trailing window. It only
+// reads r.entries[key]; retention trimming happens in Increment so that a
+// narrow-window Count can't discard history a wider-window rule still needs.
+func (r *InMemoryVelocityRepository) Count(_ context.Context, key string, window time.Duration, agg VelocityAggregation) (float64, error) {
+	cutoff := time.Now().Add(-window)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var members []string
+	for _, e := range r.entries[key] {
+		if e.ts.After(cutoff) {
+			members = append(members, velocityMember(e.ts, e.amount, e.merchant))
+		}
+	}
+
+	return aggregateVelocityMembers(members, agg), nil
+}
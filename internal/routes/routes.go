@@ -1,15 +1,58 @@
 package routes
 
 import (
-	"os" // Added import for os
+	"context"
+	"database/sql"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/kodra-pay/fraud-service/internal/cases"
+	"github.com/kodra-pay/fraud-service/internal/challenges"
+	"github.com/kodra-pay/fraud-service/internal/events"
 	"github.com/kodra-pay/fraud-service/internal/fraud"
 	"github.com/kodra-pay/fraud-service/internal/handlers"
+	"github.com/kodra-pay/fraud-service/internal/middleware"
+	"github.com/kodra-pay/fraud-service/internal/ratelimit"
 	"github.com/kodra-pay/fraud-service/internal/repository" // Add repository import
 	"github.com/kodra-pay/fraud-service/internal/services"
 )
 
+// maxVelocityRetention bounds how far back velocity aggregates are kept;
+// it must be at least as wide as the largest rule window in fraud.DefaultRules.
+const maxVelocityRetention = 24 * time.Hour
+
+// rulesFilePollInterval is how often the rules file is checked for changes
+// when FRAUD_RULES_PATH is configured.
+const rulesFilePollInterval = 5 * time.Second
+
+// defaultGeoIPRefreshInterval is how often the MaxMind databases are
+// reloaded from disk when GEOIP_REFRESH_INTERVAL is not set.
+const defaultGeoIPRefreshInterval = 24 * time.Hour
+
+// enrichmentCacheTTL bounds how long an IP/device enrichment result is
+// cached in Redis before being recomputed.
+const enrichmentCacheTTL = time.Hour
+
+// defaultWebhookRetries bounds delivery attempts for the decision-event
+// webhook publisher when FRAUD_EVENTS_WEBHOOK_RETRIES is not set.
+const defaultWebhookRetries = 3
+
+// decisionEventWorkers and decisionEventQueueDepth size the background pool
+// FraudAPIHandler publishes decision events on, so a slow consumer backs up
+// the queue instead of the API response.
+const (
+	decisionEventWorkers    = 4
+	decisionEventQueueDepth = 256
+)
+
 func Register(app *fiber.App, serviceName string) {
 	health := handlers.NewHealthHandler(serviceName)
 	health.Register(app)
@@ -17,10 +60,422 @@ func Register(app *fiber.App, serviceName string) {
 	// Initialize Fraud components
 	fraudRepo := repository.NewInMemoryFraudDataRepository()                          // Initialize in-memory repo
 	fraudDetector := fraud.NewRuleBasedFraudDetector(fraudRepo, fraud.DefaultRules()) // Pass repo and rules
-	
+	if velocityRepo := newVelocityRepository(); velocityRepo != nil {
+		fraudDetector.WithVelocityRepository(velocityRepo)
+	}
+	if ipEnricher := newIPEnricher(); ipEnricher != nil {
+		fraudDetector.WithIPEnricher(ipEnricher)
+	}
+	fraudDetector.WithDeviceEnricher(newDeviceEnricher())
+
 	transactionServiceURL := os.Getenv("TRANSACTION_SERVICE_URL")
 	fraudService := services.NewFraudService(fraudDetector, transactionServiceURL)
-	
+
+	// Ensemble scoring is opt-in: the velocity scorer is always registered,
+	// a model scorer joins in when FRAUD_MODEL_PATH points at a loaded
+	// model, and FraudService folds in the rule engine's own score under
+	// fraud.RuleEngineScorerName rather than registering it here, since it
+	// already ran once as the base detector pass.
+	registry := fraud.NewScorerRegistry()
+	registry.Register(fraud.NewVelocityScorer(time.Hour, 5), 1.0)
+	if modelPath := os.Getenv("FRAUD_MODEL_PATH"); modelPath != "" {
+		modelScorer, err := fraud.NewModelScorer(modelPath)
+		if err != nil {
+			log.Printf("fraud: skipping model scorer: %v", err)
+		} else {
+			registry.Register(modelScorer, 1.0)
+		}
+	}
+	fraudService.WithScorers(registry, fraud.AggregationWeightedSum)
+	watchForModelReloads(fraudService)
+
+	enableAsyncPipeline(fraudService, transactionServiceURL)
+
+	blacklistRepo := repository.NewInMemoryBlacklistRepository()
+	fraudDetector.WithBlacklistRepository(blacklistRepo)
+
+	caseManager := newCaseManager(blacklistRepo)
+	fraudDetector.WithCaseManager(caseManager)
+
 	fraudAPIHandler := handlers.NewFraudAPIHandler(fraudService)
+	if ruleLoader := newRuleLoader(fraudDetector); ruleLoader != nil {
+		fraudAPIHandler.WithRuleLoader(ruleLoader)
+	}
+	enableDecisionEvents(fraudAPIHandler)
+	fraudAPIHandler.WithChallengeManager(newChallengeManager())
+	merchantRepo := newMerchantRepository()
+	if rateLimiter := newRateLimiter(merchantRepo, fraudAPIHandler.DecisionEmitter()); rateLimiter != nil {
+		fraudAPIHandler.WithRateLimiter(rateLimiter)
+	}
 	fraudAPIHandler.Register(app)
+
+	caseAPIHandler := handlers.NewCaseAPIHandler(caseManager, newAPIKeyIdentities("CASE_API_KEYS"))
+	caseAPIHandler.Register(app)
+
+	merchantAPIHandler := handlers.NewMerchantAPIHandler(ratelimit.NewManager(merchantRepo), newAPIKeyIdentities("MERCHANT_ADMIN_API_KEYS"))
+	merchantAPIHandler.Register(app)
+}
+
+// newCaseManager wires the analyst case workflow: cases are persisted to
+// Postgres when POSTGRES_DSN is configured, falling back to an in-memory
+// repository otherwise. Confirmed-fraud verdicts are recorded to an
+// in-memory feedback repository until a real training warehouse is wired
+// in, and blacklisted via blacklistRepo, the same repository the detector
+// consults so a confirmed verdict takes effect immediately.
+func newCaseManager(blacklistRepo repository.BlacklistRepository) *cases.CaseManager {
+	var caseRepo cases.CaseRepository = cases.NewInMemoryCaseRepository()
+	if dsn := os.Getenv("POSTGRES_DSN"); dsn != "" {
+		db, err := sql.Open("postgres", dsn)
+		if err != nil {
+			log.Printf("cases: failed to open case database, falling back to in-memory: %v", err)
+		} else {
+			caseRepo = cases.NewPostgresCaseRepository(db)
+		}
+	}
+	return cases.NewCaseManager(caseRepo, cases.NewInMemoryFeedbackRepository(), blacklistRepo)
+}
+
+// newAPIKeyIdentities parses an env var formatted "key:role,key2:role2"
+// into RBAC identities, the shared format CASE_API_KEYS and
+// MERCHANT_ADMIN_API_KEYS are both read with. Returns nil (no auth) when
+// envVar is unset, which should only happen in local development.
+func newAPIKeyIdentities(envVar string) []middleware.APIKeyIdentity {
+	raw := os.Getenv(envVar)
+	if raw == "" {
+		return nil
+	}
+	var identities []middleware.APIKeyIdentity
+	for _, pair := range strings.Split(raw, ",") {
+		key, role, ok := strings.Cut(pair, ":")
+		if !ok || key == "" || role == "" {
+			continue
+		}
+		identities = append(identities, middleware.APIKeyIdentity{Key: key, Role: role})
+	}
+	return identities
+}
+
+// newChallengeManager wires the step-up verification subsystem backed by an
+// in-memory challenge store and the logging OTPFactorDispatcher; a
+// multi-instance deployment needs a shared Repository (see
+// challenges.InMemoryRepository) and a real SMS/email/3DS FactorDispatcher
+// before this is production-ready.
+func newChallengeManager() *challenges.Manager {
+	return challenges.NewManager(challenges.NewInMemoryRepository(), challenges.NewOTPFactorDispatcher())
+}
+
+// enableAsyncPipeline wires FraudService to run fraud evaluation against an
+// event stream instead of inline, when EVENTS_DRIVER is configured. The
+// synchronous path (CheckTransaction) keeps working either way.
+func enableAsyncPipeline(fraudService *services.FraudService, transactionServiceURL string) {
+	driver := os.Getenv("EVENTS_DRIVER")
+	if driver == "" {
+		return
+	}
+
+	var publisher events.Publisher
+	var subscriber events.Subscriber
+
+	switch driver {
+	case "nats":
+		broker, err := events.NewNATSBroker(os.Getenv("EVENTS_NATS_URL"))
+		if err != nil {
+			log.Printf("events: failed to start NATS broker, async mode disabled: %v", err)
+			return
+		}
+		publisher, subscriber = broker, broker
+	case "kafka":
+		brokers := strings.Split(os.Getenv("EVENTS_KAFKA_BROKERS"), ",")
+		broker := events.NewKafkaBroker(brokers, "fraud-service")
+		publisher, subscriber = broker, broker
+	default:
+		log.Printf("events: unknown EVENTS_DRIVER %q, async mode disabled", driver)
+		return
+	}
+
+	var outbox events.OutboxStore
+	if dsn := os.Getenv("POSTGRES_DSN"); dsn != "" {
+		db, err := sql.Open("postgres", dsn)
+		if err != nil {
+			log.Printf("events: failed to open outbox database, continuing without at-least-once delivery: %v", err)
+		} else {
+			outbox = events.NewPostgresOutboxStore(db)
+			go events.NewDispatcher(outbox, 100).Run(context.Background(), 2*time.Second)
+		}
+	}
+
+	destination := strings.TrimRight(transactionServiceURL, "/") + "/fraud-decisions"
+	if err := fraudService.EnableAsync(context.Background(), publisher, subscriber, outbox, destination); err != nil {
+		log.Printf("events: failed to start async fraud evaluation worker: %v", err)
+	}
+}
+
+// enableDecisionEvents wires FraudAPIHandler to fire-and-forget a
+// fraud.decision / payment_link.flagged / channel.validation event after
+// every decision, selected by FRAUD_EVENTS_DRIVER ("webhook", "nats",
+// "kafka"). This is independent of EVENTS_DRIVER/enableAsyncPipeline, which
+// governs whether transactions are *scored* asynchronously rather than
+// whether a decision event is emitted afterwards. Publishing runs on a
+// bounded AsyncEmitter worker pool so a slow or unreachable consumer never
+// adds latency to the API response. When POSTGRES_DSN is also set, events
+// are additionally queued in an outbox so POST /fraud/admin/events/replay
+// can redeliver a time window of them later.
+func enableDecisionEvents(h *handlers.FraudAPIHandler) {
+	driver := os.Getenv("FRAUD_EVENTS_DRIVER")
+	if driver == "" {
+		return
+	}
+
+	publisher, destination := newDecisionEventPublisher(driver)
+	if publisher == nil {
+		return
+	}
+
+	var outbox events.OutboxStore
+	if dsn := os.Getenv("POSTGRES_DSN"); dsn != "" && destination != "" {
+		db, err := sql.Open("postgres", dsn)
+		if err != nil {
+			log.Printf("events: failed to open outbox database, decision event replay disabled: %v", err)
+		} else {
+			outbox = events.NewPostgresOutboxStore(db)
+			h.WithDispatcher(events.NewDispatcher(outbox, 100))
+		}
+	}
+
+	emitter := events.NewAsyncEmitter(
+		events.NewOutboxEnqueuingPublisher(publisher, outbox, destination),
+		decisionEventWorkers,
+		decisionEventQueueDepth,
+	)
+	h.WithDecisionEmitter(emitter)
+}
+
+// newDecisionEventPublisher builds the Publisher decision events are sent
+// to for driver, along with the destination URL a Dispatcher should
+// redeliver to (only meaningful for the webhook driver, since Dispatcher
+// always redelivers via HTTP POST).
+func newDecisionEventPublisher(driver string) (publisher events.Publisher, destination string) {
+	switch driver {
+	case "webhook":
+		url := os.Getenv("FRAUD_EVENTS_WEBHOOK_URL")
+		if url == "" {
+			log.Printf("events: FRAUD_EVENTS_DRIVER=webhook requires FRAUD_EVENTS_WEBHOOK_URL, decision events disabled")
+			return nil, ""
+		}
+		retries := defaultWebhookRetries
+		if raw := os.Getenv("FRAUD_EVENTS_WEBHOOK_RETRIES"); raw != "" {
+			if parsed, err := strconv.Atoi(raw); err == nil {
+				retries = parsed
+			}
+		}
+		return events.NewWebhookPublisher(url, []byte(os.Getenv("FRAUD_EVENTS_WEBHOOK_SECRET")), retries), url
+	case "nats":
+		broker, err := events.NewNATSBroker(os.Getenv("EVENTS_NATS_URL"))
+		if err != nil {
+			log.Printf("events: failed to start NATS broker, decision events disabled: %v", err)
+			return nil, ""
+		}
+		return broker, ""
+	case "kafka":
+		brokers := strings.Split(os.Getenv("EVENTS_KAFKA_BROKERS"), ",")
+		return events.NewKafkaBroker(brokers, "fraud-service"), ""
+	default:
+		log.Printf("events: unknown FRAUD_EVENTS_DRIVER %q, decision events disabled", driver)
+		return nil, ""
+	}
+}
+
+// newRuleLoader wires a DSL-driven, hot-reloadable rule set when
+// FRAUD_RULES_PATH is configured, replacing the compiled-in fraud.DefaultRules.
+func newRuleLoader(detector *fraud.RuleBasedFraudDetector) *fraud.RuleLoader {
+	path := os.Getenv("FRAUD_RULES_PATH")
+	if path == "" {
+		return nil
+	}
+
+	loader := fraud.NewRuleLoader(path, detector)
+	if err := loader.Load(); err != nil {
+		log.Printf("fraud: keeping default rules, failed to load %s: %v", path, err)
+		return loader
+	}
+	if errs := loader.Watch(rulesFilePollInterval); errs != nil {
+		go func() {
+			for err := range errs {
+				log.Printf("fraud: rule reload failed: %v", err)
+			}
+		}()
+	}
+	return loader
+}
+
+// newIPEnricher builds a MaxMind-backed IP enricher when GEOIP_CITY_DB_PATH
+// is configured (ASN/ISP enrichment additionally requires GEOIP_ASN_DB_PATH),
+// wrapping it with a Redis-backed cache when REDIS_ADDR is also set, and
+// starting a background reload loop so a newer GeoLite2 release can be
+// rolled out without a restart. Returns nil (IP enrichment disabled)
+// otherwise.
+func newIPEnricher() repository.IPEnricher {
+	cityDBPath := os.Getenv("GEOIP_CITY_DB_PATH")
+	if cityDBPath == "" {
+		return nil
+	}
+
+	enricher, err := repository.NewMaxMindIPEnricher(
+		cityDBPath,
+		os.Getenv("GEOIP_ASN_DB_PATH"),
+		splitNonEmpty(os.Getenv("TOR_EXIT_NODES")),
+		parseASNs(os.Getenv("HOSTING_ASNS")),
+	)
+	if err != nil {
+		log.Printf("ip enrichment: failed to start, disabling: %v", err)
+		return nil
+	}
+	watchForGeoIPReloads(enricher)
+
+	var ipEnricher repository.IPEnricher = enricher
+	if addr := os.Getenv("REDIS_ADDR"); addr != "" {
+		client := redis.NewClient(&redis.Options{Addr: addr, Password: os.Getenv("REDIS_PASSWORD")})
+		ipEnricher = repository.NewCachedIPEnricher(enricher, client, enrichmentCacheTTL)
+	}
+	return ipEnricher
+}
+
+// newDeviceEnricher builds the heuristic device-fingerprint enricher,
+// wrapping it with a Redis-backed cache when REDIS_ADDR is configured.
+// Unlike IP enrichment this needs no external database, so it's always on.
+func newDeviceEnricher() repository.DeviceEnricher {
+	var deviceEnricher repository.DeviceEnricher = repository.NewHeuristicDeviceEnricher()
+	if addr := os.Getenv("REDIS_ADDR"); addr != "" {
+		client := redis.NewClient(&redis.Options{Addr: addr, Password: os.Getenv("REDIS_PASSWORD")})
+		deviceEnricher = repository.NewCachedDeviceEnricher(deviceEnricher, client, enrichmentCacheTTL)
+	}
+	return deviceEnricher
+}
+
+// watchForGeoIPReloads periodically reloads enricher's MaxMind databases so
+// operators can roll out a newer GeoLite2 release without a restart. The
+// cadence is configurable via GEOIP_REFRESH_INTERVAL (default 24h).
+func watchForGeoIPReloads(enricher *repository.MaxMindIPEnricher) {
+	interval := defaultGeoIPRefreshInterval
+	if raw := os.Getenv("GEOIP_REFRESH_INTERVAL"); raw != "" {
+		if parsed, err := time.ParseDuration(raw); err == nil {
+			interval = parsed
+		}
+	}
+	ticker := time.NewTicker(interval)
+	go func() {
+		for range ticker.C {
+			if err := enricher.Reload(); err != nil {
+				log.Printf("ip enrichment: failed to reload GeoIP databases: %v", err)
+			}
+		}
+	}()
+}
+
+// splitNonEmpty splits a comma-separated env var, dropping empty segments.
+func splitNonEmpty(raw string) []string {
+	if raw == "" {
+		return nil
+	}
+	var out []string
+	for _, s := range strings.Split(raw, ",") {
+		if s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// parseASNs parses a comma-separated list of ASN numbers, skipping any
+// segment that doesn't parse instead of failing the whole list.
+func parseASNs(raw string) []uint {
+	if raw == "" {
+		return nil
+	}
+	var out []uint
+	for _, s := range strings.Split(raw, ",") {
+		if s == "" {
+			continue
+		}
+		n, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			continue
+		}
+		out = append(out, uint(n))
+	}
+	return out
+}
+
+// newVelocityRepository builds a Redis-backed velocity repository when
+// REDIS_ADDR is configured, falling back to nil (leaving the detector's
+// default in-memory repository in place) otherwise.
+func newVelocityRepository() repository.VelocityRepository {
+	addr := os.Getenv("REDIS_ADDR")
+	if addr == "" {
+		return nil
+	}
+	db, err := strconv.Atoi(os.Getenv("REDIS_DB"))
+	if err != nil {
+		db = 0
+	}
+	client := redis.NewClient(&redis.Options{
+		Addr:     addr,
+		Password: os.Getenv("REDIS_PASSWORD"),
+		DB:       db,
+	})
+	return repository.NewRedisVelocityRepository(client, maxVelocityRetention)
+}
+
+// newMerchantRepository builds the repository rate-limit tiers are loaded
+// from: Postgres when POSTGRES_DSN is configured, falling back to an
+// in-memory repository (empty until populated via the /admin/merchants API)
+// otherwise.
+func newMerchantRepository() ratelimit.MerchantRepository {
+	if dsn := os.Getenv("POSTGRES_DSN"); dsn != "" {
+		db, err := sql.Open("postgres", dsn)
+		if err != nil {
+			log.Printf("ratelimit: failed to open merchant database, falling back to in-memory: %v", err)
+		} else {
+			return ratelimit.NewPostgresMerchantRepository(db)
+		}
+	}
+	return ratelimit.NewInMemoryMerchantRepository()
+}
+
+// newRateLimiter builds the per-merchant rate-limit middleware when
+// REDIS_ADDR is configured, so FraudAPIHandler.Register falls back to its
+// fixed in-memory limiter otherwise. emitter (FraudAPIHandler's
+// decisionEmitter, reused rather than standing up a second worker pool) may
+// be nil, in which case quota.exceeded is simply not published.
+func newRateLimiter(merchantRepo ratelimit.MerchantRepository, emitter *events.AsyncEmitter) fiber.Handler {
+	addr := os.Getenv("REDIS_ADDR")
+	if addr == "" {
+		return nil
+	}
+	db, err := strconv.Atoi(os.Getenv("REDIS_DB"))
+	if err != nil {
+		db = 0
+	}
+	client := redis.NewClient(&redis.Options{
+		Addr:     addr,
+		Password: os.Getenv("REDIS_PASSWORD"),
+		DB:       db,
+	})
+	return middleware.RateLimit(merchantRepo, ratelimit.NewLimiter(client), emitter)
+}
+
+// watchForModelReloads reloads every model-backed scorer on SIGHUP so
+// operators can roll out a new model without restarting the service.
+func watchForModelReloads(fraudService *services.FraudService) {
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+	go func() {
+		for range sighup {
+			if err := fraudService.ReloadModels(); err != nil {
+				log.Printf("fraud: model reload failed: %v", err)
+				continue
+			}
+			log.Printf("fraud: models reloaded")
+		}
+	}()
 }
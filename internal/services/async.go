@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/kodra-pay/fraud-service/internal/events"
+)
+
+const (
+	// TransactionSubmittedSubject is published by CheckTransactionAsync and
+	// consumed by the async scoring worker started in EnableAsync.
+	TransactionSubmittedSubject = "transaction.submitted"
+	// FraudDecisionsSubject carries the FraudDecision produced for each
+	// transaction.submitted event, for downstream consumers.
+	FraudDecisionsSubject = "fraud.decisions"
+)
+
+// AsyncPipeline lets FraudService.CheckTransaction run decoupled from the
+// synchronous request path: CheckTransactionAsync publishes the transaction
+// instead of scoring it inline, a worker goroutine scores it against the
+// current ruleset, and the resulting decision is both published to
+// fraud.decisions and durably queued in an outbox for at-least-once delivery
+// back to the transaction service.
+type AsyncPipeline struct {
+	publisher  events.Publisher
+	subscriber events.Subscriber
+	outbox     events.OutboxStore
+
+	decisionDestination string
+	metrics             events.ConsumerMetrics
+}
+
+// EnableAsync wires publisher/subscriber/outbox into s and starts the worker
+// that scores transaction.submitted events. decisionDestination is the
+// transaction-service URL decisions are delivered to via the outbox.
+func (s *FraudService) EnableAsync(ctx context.Context, publisher events.Publisher, subscriber events.Subscriber, outbox events.OutboxStore, decisionDestination string) error {
+	s.async = &AsyncPipeline{
+		publisher:           publisher,
+		subscriber:          subscriber,
+		outbox:              outbox,
+		decisionDestination: decisionDestination,
+	}
+
+	handler := s.async.metrics.Instrument(func(ctx context.Context, event events.Event) error {
+		var transactionData map[string]interface{}
+		if err := json.Unmarshal(event.Data, &transactionData); err != nil {
+			return fmt.Errorf("async: failed to decode transaction payload: %w", err)
+		}
+
+		decision, err := s.CheckTransaction(ctx, transactionData)
+		if err != nil {
+			return fmt.Errorf("async: failed to score transaction: %w", err)
+		}
+
+		decisionEvent, err := events.NewEvent(FraudDecisionsSubject, decision)
+		if err != nil {
+			return fmt.Errorf("async: failed to encode decision: %w", err)
+		}
+
+		if err := s.async.publisher.Publish(ctx, FraudDecisionsSubject, decisionEvent); err != nil {
+			return fmt.Errorf("async: failed to publish decision: %w", err)
+		}
+
+		if s.async.outbox != nil {
+			if err := s.async.outbox.Enqueue(ctx, s.async.decisionDestination, decisionEvent); err != nil {
+				return fmt.Errorf("async: failed to enqueue decision for delivery: %w", err)
+			}
+		}
+
+		return nil
+	})
+
+	return subscriber.Subscribe(ctx, TransactionSubmittedSubject, handler)
+}
+
+// AsyncEnabled reports whether EnableAsync has been called.
+func (s *FraudService) AsyncEnabled() bool { return s.async != nil }
+
+// AsyncMetrics returns the current async worker's processing metrics, or the
+// zero Snapshot if async mode is not enabled.
+func (s *FraudService) AsyncMetrics() events.Snapshot {
+	if s.async == nil {
+		return events.Snapshot{}
+	}
+	return s.async.metrics.Snapshot()
+}
+
+// CheckTransactionAsync publishes transactionData to TransactionSubmittedSubject
+// instead of scoring it inline, decoupling fraud evaluation from the
+// synchronous payment path. The caller must poll fraud.decisions (or its own
+// downstream delivery of the outbox) for the eventual result.
+func (s *FraudService) CheckTransactionAsync(ctx context.Context, transactionData map[string]interface{}) error {
+	if s.async == nil {
+		return fmt.Errorf("async fraud evaluation is not enabled")
+	}
+	event, err := events.NewEvent(TransactionSubmittedSubject, transactionData)
+	if err != nil {
+		return fmt.Errorf("async: failed to encode transaction: %w", err)
+	}
+	if err := s.async.publisher.Publish(ctx, TransactionSubmittedSubject, event); err != nil {
+		return fmt.Errorf("async: failed to publish transaction: %w", err)
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -16,6 +17,12 @@ import (
 	"github.com/kodra-pay/fraud-service/internal/fraud"
 )
 
+// ErrTransactionNotFound is returned by GetTransactionDetailsByReference
+// when the transaction service has no transaction with that reference, so
+// callers can distinguish a not-found from any other transport/response
+// error with errors.Is instead of matching on the error string.
+var ErrTransactionNotFound = errors.New("transaction not found")
+
 // TransactionResponse DTO for returning transaction information (copied from transaction-service/internal/dto/dto.go)
 type TransactionResponse struct {
 	ID            int       `json:"id"`
@@ -31,11 +38,24 @@ type TransactionResponse struct {
 	CreatedAt     time.Time `json:"created_at"`
 }
 
+// defaultScorerTimeout bounds how long FraudService waits for any single
+// scorer before excluding it from the ensemble result.
+const defaultScorerTimeout = 500 * time.Millisecond
+
 // FraudService encapsulates the business logic for fraud detection.
 
 type FraudService struct {
 	detector fraud.FraudDetector
 
+	scorers       *fraud.ScorerRegistry
+	aggregation   fraud.Aggregation
+	scorerTimeout time.Duration
+
+	linkSigner             *PaymentLinkSigner
+	crossCheckPaymentLinks bool
+
+	async *AsyncPipeline
+
 	transactionServiceURL string
 }
 
@@ -57,20 +77,98 @@ func NewFraudService(detector fraud.FraudDetector, transactionServiceURL string)
 
 	}
 
-	return &FraudService{detector: detector, transactionServiceURL: transactionServiceURL}
+	return &FraudService{
+		detector:               detector,
+		scorers:                nil,
+		aggregation:            fraud.AggregationWeightedSum,
+		scorerTimeout:          defaultScorerTimeout,
+		linkSigner:             NewPaymentLinkSignerFromEnv(),
+		crossCheckPaymentLinks: os.Getenv("PAYMENT_LINK_CROSS_CHECK") != "false",
+		transactionServiceURL:  transactionServiceURL,
+	}
 
 }
 
-// CheckTransaction orchestrates the fraud check for a given transaction.
+// WithScorers enables ensemble scoring: in addition to the rule-based
+// detector, CheckTransaction will dispatch to every scorer registered in
+// registry concurrently and combine the results using mode.
+func (s *FraudService) WithScorers(registry *fraud.ScorerRegistry, mode fraud.Aggregation) *FraudService {
+	s.scorers = registry
+	s.aggregation = mode
+	return s
+}
 
-func (s *FraudService) CheckTransaction(ctx context.Context, transactionData map[string]interface{}) (fraud.FraudDecision, error) {
+// Models reports the identity of every model-backed scorer currently
+// registered, for the /models admin endpoint.
+func (s *FraudService) Models() []fraud.ModelInfo {
+	if s.scorers == nil {
+		return nil
+	}
+	var infos []fraud.ModelInfo
+	for _, scorer := range s.scorers.Enabled() {
+		if ms, ok := scorer.(*fraud.ModelScorer); ok {
+			infos = append(infos, ms.Info())
+		}
+	}
+	return infos
+}
 
-	decision, err := s.detector.CheckTransaction(ctx, transactionData)
+// ReloadModels re-reads every model-backed scorer's file from disk. It is
+// wired up to SIGHUP so operators can roll out a new model without a
+// restart.
+func (s *FraudService) ReloadModels() error {
+	if s.scorers == nil {
+		return nil
+	}
+	for _, scorer := range s.scorers.Enabled() {
+		if ms, ok := scorer.(*fraud.ModelScorer); ok {
+			if err := ms.Reload(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
 
+// CheckTransaction orchestrates the fraud check for a given transaction. The
+// rule engine runs exactly once: when ensemble scorers are configured via
+// WithScorers, its score is folded into the combined score under
+// fraud.RuleEngineScorerName rather than dispatching it a second time as a
+// registered Scorer, since the rule engine's side effects (velocity
+// counters, auto-opened cases) must only happen once per transaction. Every
+// other scorer's partial score is surfaced in FraudDecision.Reasons so
+// operators can audit how the final score was reached.
+func (s *FraudService) CheckTransaction(ctx context.Context, transactionData map[string]interface{}) (fraud.FraudDecision, error) {
+	decision, err := s.detector.CheckTransaction(ctx, transactionData)
 	if err != nil {
-
 		return fraud.FraudDecision{}, err
+	}
+
+	if s.scorers == nil {
+		return decision, nil
+	}
+
+	results := s.scorers.Dispatch(ctx, transactionData, s.scorerTimeout)
+	results = append(results, fraud.ScorerResult{Source: fraud.RuleEngineScorerName, Score: decision.OverallScore})
+
+	for _, res := range results {
+		if res.Source == fraud.RuleEngineScorerName {
+			continue
+		}
+		if res.Err != nil {
+			decision.Reasons = append(decision.Reasons, fmt.Sprintf("%s: scoring failed (%v)", res.Source, res.Err))
+			continue
+		}
+		decision.Reasons = append(decision.Reasons, fmt.Sprintf("%s: score=%.1f", res.Source, res.Score))
+		decision.Reasons = append(decision.Reasons, res.Reasons...)
+	}
 
+	decision.OverallScore = s.scorers.Combine(results, s.aggregation)
+	switch {
+	case decision.OverallScore >= 100:
+		decision.Decision = "deny"
+	case decision.OverallScore >= 50 && decision.Decision != "deny":
+		decision.Decision = "challenge"
 	}
 
 	return decision, nil
@@ -128,6 +226,13 @@ func (s *FraudService) Rules(_ context.Context) []string {
 
 }
 
+// TransactionServiceURL returns the base URL GetTransactionDetailsByReference
+// queries, so handlers can build HAL links into resources the transaction
+// service owns (merchants, refunds) without duplicating the env var lookup.
+func (s *FraudService) TransactionServiceURL() string {
+	return s.transactionServiceURL
+}
+
 // GetTransactionDetailsByReference fetches transaction details from the transaction service by reference.
 
 func (s *FraudService) GetTransactionDetailsByReference(ctx context.Context, reference string) (*TransactionResponse, error) {
@@ -160,7 +265,7 @@ func (s *FraudService) GetTransactionDetailsByReference(ctx context.Context, ref
 
 	if resp.StatusCode == http.StatusNotFound {
 
-		return nil, fmt.Errorf("transaction with reference %s not found", reference)
+		return nil, fmt.Errorf("%w: reference %s", ErrTransactionNotFound, reference)
 
 	}
 
@@ -184,100 +289,105 @@ func (s *FraudService) GetTransactionDetailsByReference(ctx context.Context, ref
 
 }
 
-// ValidatePaymentLink parses a payment link URL and validates its parameters against transaction service.
+// IssuePaymentLink signs params with the service's default signing key and
+// returns the query string a payment link should carry (ref, merchant_id,
+// amount, currency, mode, exp, kid, sig), ready to be appended to whatever
+// base URL the caller serves payment links from.
+func (s *FraudService) IssuePaymentLink(params PaymentLinkParams) (string, error) {
+	if !s.linkSigner.Configured() {
+		return "", fmt.Errorf("no payment link signing key configured")
+	}
+	kid, sig, err := s.linkSigner.Sign(params)
+	if err != nil {
+		return "", err
+	}
 
-func (s *FraudService) ValidatePaymentLink(ctx context.Context, linkUrl string) (bool, string, error) {
+	values := url.Values{}
+	values.Set("ref", params.Reference)
+	values.Set("merchant_id", strconv.Itoa(params.MerchantID))
+	values.Set("amount", strconv.FormatInt(params.Amount, 10))
+	values.Set("currency", params.Currency)
+	values.Set("mode", params.Mode)
+	values.Set("exp", strconv.FormatInt(params.Exp.Unix(), 10))
+	values.Set("kid", kid)
+	values.Set("sig", sig)
+	return values.Encode(), nil
+}
 
+// ValidatePaymentLink parses a payment link URL and validates it. Links
+// carrying a signature are verified via HMAC in constant time first: a
+// mismatched kid, signature, or an expired exp is reported as a distinct
+// reason so operators can tell tampering apart from a stale link. Only once
+// the signature checks out does the (optional, config-gated) transaction-
+// service cross-check run, as a defense-in-depth layer rather than the
+// primary trust boundary. Links with no signature at all fall back entirely
+// to the cross-check, for links issued before this scheme existed.
+func (s *FraudService) ValidatePaymentLink(ctx context.Context, linkUrl string) (bool, string, error) {
 	parsedURL, err := url.Parse(linkUrl)
-
 	if err != nil {
-
 		return true, "Invalid payment link URL format", nil // Suspicious due to malformed URL
-
 	}
 
-	queryParams := parsedURL.Query()
-
-	// Extract parameters from URL
-
-	ref := queryParams.Get("ref")
-
-	merchantIDStr := queryParams.Get("merchant_id")
-
-	amountStr := queryParams.Get("amount")
-
-			currency := queryParams.Get("currency")
-
-			mode := queryParams.Get("mode") // For future use, if 'open' links have different validation
-
-			_ = mode
-
-	if ref == "" || merchantIDStr == "" || amountStr == "" || currency == "" {
-
+	parsed := parsePaymentLinkQuery(parsedURL.Query())
+	if !parsed.ok {
 		return true, "Missing required parameters in payment link (ref, merchant_id, amount, currency)", nil
-
 	}
 
-	// Convert merchantID and amount
-
-	merchantID, err := strconv.Atoi(merchantIDStr)
-
-	if err != nil {
-
-		return true, "Invalid merchant_id format in payment link", nil
-
+	if parsed.sig == "" {
+		return s.crossCheckPaymentLink(ctx, parsed.params)
 	}
 
-	amount, err := strconv.ParseInt(amountStr, 10, 64)
+	if parsed.kid == "" {
+		return true, "Payment link is missing its key ID (kid)", nil
+	}
 
+	valid, err := s.linkSigner.Verify(parsed.kid, parsed.params, parsed.sig)
 	if err != nil {
+		return true, fmt.Sprintf("Error verifying payment link signature: %v", err), nil
+	}
+	if !valid {
+		return true, fmt.Sprintf("Payment link signature is invalid for key %s (tampered parameters)", parsed.kid), nil
+	}
 
-		return true, "Invalid amount format in payment link", nil
-
+	if !parsed.params.Exp.IsZero() && time.Now().After(parsed.params.Exp) {
+		return true, fmt.Sprintf("Payment link signature is valid but expired at %s", parsed.params.Exp.Format(time.RFC3339)), nil
 	}
 
-	// Fetch original transaction details
+	if s.crossCheckPaymentLinks {
+		suspicious, reason, err := s.crossCheckPaymentLink(ctx, parsed.params)
+		if err != nil {
+			return false, "", err
+		}
+		if suspicious {
+			return true, fmt.Sprintf("Payment link signature is valid but failed cross-check: %s", reason), nil
+		}
+	}
 
-	originalTx, err := s.GetTransactionDetailsByReference(ctx, ref)
+	return false, "Payment link signature is valid", nil
+}
 
+// crossCheckPaymentLink validates payment link parameters by round-tripping
+// to the transaction service. It is the sole check for links with no
+// signature, and an optional defense-in-depth check for signed ones.
+func (s *FraudService) crossCheckPaymentLink(ctx context.Context, params PaymentLinkParams) (bool, string, error) {
+	originalTx, err := s.GetTransactionDetailsByReference(ctx, params.Reference)
 	if err != nil {
-
 		// If transaction not found, it could be tampered or a non-existent link
-
-		if strings.Contains(err.Error(), "not found") {
-
-			return true, fmt.Sprintf("Transaction reference %s not found for payment link", ref), nil
-
+		if errors.Is(err, ErrTransactionNotFound) {
+			return true, fmt.Sprintf("Transaction reference %s not found for payment link", params.Reference), nil
 		}
-
 		return true, fmt.Sprintf("Error fetching original transaction details: %v", err), nil
-
 	}
 
-	// Validate against original transaction details
-
-	if originalTx.MerchantID != merchantID {
-
-		return true, fmt.Sprintf("Merchant ID mismatch: link has %d, original has %d", merchantID, originalTx.MerchantID), nil
-
+	if originalTx.MerchantID != params.MerchantID {
+		return true, fmt.Sprintf("Merchant ID mismatch: link has %d, original has %d", params.MerchantID, originalTx.MerchantID), nil
 	}
-
-	if originalTx.Amount != amount {
-
-		return true, fmt.Sprintf("Amount mismatch: link has %d, original has %d", amount, originalTx.Amount), nil
-
+	if originalTx.Amount != params.Amount {
+		return true, fmt.Sprintf("Amount mismatch: link has %d, original has %d", params.Amount, originalTx.Amount), nil
 	}
-
-	if originalTx.Currency != currency {
-
-		return true, fmt.Sprintf("Currency mismatch: link has %s, original has %s", currency, originalTx.Currency), nil
-
+	if originalTx.Currency != params.Currency {
+		return true, fmt.Sprintf("Currency mismatch: link has %s, original has %s", params.Currency, originalTx.Currency), nil
 	}
 
-	// Additional checks for 'mode' or other parameters can be added here.
-
-	// For example, if mode=open implies amount should be 0 in the original tx or be ignored.
-
 	return false, "Payment link is legitimate", nil
-
 }
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PaymentLinkParams describes the parameters a payment link binds together
+// under its signature.
+type PaymentLinkParams struct {
+	Reference  string
+	MerchantID int
+	Amount     int64
+	Currency   string
+	Mode       string
+	Exp        time.Time
+}
+
+// canonical builds the deterministic string the HMAC is computed over. Field
+// order and the "|" separator must never change without a key rotation,
+// since it would invalidate every signature issued under the old encoding.
+func (p PaymentLinkParams) canonical() string {
+	return fmt.Sprintf("%s|%d|%d|%s|%s|%d", p.Reference, p.MerchantID, p.Amount, p.Currency, p.Mode, p.Exp.Unix())
+}
+
+// PaymentLinkSigner issues and verifies HMAC-signed payment links. It holds a
+// keyset indexed by key ID (kid) so secrets can be rotated without
+// invalidating links signed under an older key: new links are always signed
+// with defaultKid, but verification accepts any kid still present in keys.
+type PaymentLinkSigner struct {
+	keys       map[string]string
+	defaultKid string
+}
+
+// NewPaymentLinkSignerFromEnv loads a keyset from PAYMENT_LINK_KEYS, a
+// comma-separated list of "kid:secret" pairs (e.g. "2024-01:abc,2024-02:def").
+// The first entry is used as the default signing key. As a convenience for
+// single-key deployments, PAYMENT_LINK_SECRET is accepted under the implicit
+// kid "default" when PAYMENT_LINK_KEYS is unset.
+func NewPaymentLinkSignerFromEnv() *PaymentLinkSigner {
+	keys := make(map[string]string)
+	var defaultKid string
+
+	if raw := os.Getenv("PAYMENT_LINK_KEYS"); raw != "" {
+		for _, pair := range strings.Split(raw, ",") {
+			kid, secret, ok := strings.Cut(pair, ":")
+			if !ok || kid == "" || secret == "" {
+				continue
+			}
+			keys[kid] = secret
+			if defaultKid == "" {
+				defaultKid = kid
+			}
+		}
+	} else if secret := os.Getenv("PAYMENT_LINK_SECRET"); secret != "" {
+		keys["default"] = secret
+		defaultKid = "default"
+	}
+
+	return &PaymentLinkSigner{keys: keys, defaultKid: defaultKid}
+}
+
+// Configured reports whether at least one signing key is available.
+func (s *PaymentLinkSigner) Configured() bool {
+	return len(s.keys) > 0
+}
+
+// Sign returns the kid and base64url-encoded HMAC-SHA256 signature for
+// params, using the signer's default key.
+func (s *PaymentLinkSigner) Sign(params PaymentLinkParams) (kid, sig string, err error) {
+	if s.defaultKid == "" {
+		return "", "", fmt.Errorf("no payment link signing key configured")
+	}
+	return s.defaultKid, s.sign(s.defaultKid, params), nil
+}
+
+func (s *PaymentLinkSigner) sign(kid string, params PaymentLinkParams) string {
+	mac := hmac.New(sha256.New, []byte(s.keys[kid]))
+	mac.Write([]byte(params.canonical()))
+	return base64.URLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks sig against params under kid in constant time. It returns
+// false, nil if the kid is not recognized (rather than an error), so callers
+// can distinguish "unknown key" from an unexpected internal failure.
+func (s *PaymentLinkSigner) Verify(kid string, params PaymentLinkParams, sig string) (bool, error) {
+	if _, ok := s.keys[kid]; !ok {
+		return false, nil
+	}
+	expected := s.sign(kid, params)
+	return hmac.Equal([]byte(expected), []byte(sig)), nil
+}
+
+// paymentLinkQueryParams pulls PaymentLinkParams plus the kid/sig envelope
+// out of a parsed payment link URL's query string.
+type paymentLinkQueryParams struct {
+	params PaymentLinkParams
+	kid    string
+	sig    string
+	ok     bool
+}
+
+func parsePaymentLinkQuery(query map[string][]string) paymentLinkQueryParams {
+	get := func(key string) string {
+		if v := query[key]; len(v) > 0 {
+			return v[0]
+		}
+		return ""
+	}
+
+	ref := get("ref")
+	merchantIDStr := get("merchant_id")
+	amountStr := get("amount")
+	currency := get("currency")
+	mode := get("mode")
+	expStr := get("exp")
+	kid := get("kid")
+	sig := get("sig")
+
+	if ref == "" || merchantIDStr == "" || amountStr == "" || currency == "" {
+		return paymentLinkQueryParams{}
+	}
+
+	merchantID, err := strconv.Atoi(merchantIDStr)
+	if err != nil {
+		return paymentLinkQueryParams{}
+	}
+	amount, err := strconv.ParseInt(amountStr, 10, 64)
+	if err != nil {
+		return paymentLinkQueryParams{}
+	}
+
+	var exp time.Time
+	if expStr != "" {
+		expUnix, err := strconv.ParseInt(expStr, 10, 64)
+		if err != nil {
+			return paymentLinkQueryParams{}
+		}
+		exp = time.Unix(expUnix, 0)
+	}
+
+	return paymentLinkQueryParams{
+		params: PaymentLinkParams{
+			Reference:  ref,
+			MerchantID: merchantID,
+			Amount:     amount,
+			Currency:   currency,
+			Mode:       mode,
+			Exp:        exp,
+		},
+		kid: kid,
+		sig: sig,
+		ok:  true,
+	}
+}